@@ -3,22 +3,319 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/limbicnode/go2ts/pkg/go2ts"
 )
 
 func main() {
-	inputDir := flag.String("in", "./internal/model", "Directory to scan Go structs")
-	outputFile := flag.String("out", "types.ts", "Output TypeScript file path")
+	inputDir := flag.String("in", "./internal/model", "Directory or package import path to scan Go structs; a comma-separated list merges several into one output")
+	var outputFiles stringList
+	stdout := flag.Bool("stdout", false, "Write the output to standard output instead of files; -out and -merge-into are ignored")
+	flag.Var(&outputFiles, "out", "Output TypeScript file path; repeat to write the same output to several files (default \"types.ts\")")
+	mergeInto := flag.String("merge-into", "", "Existing TypeScript file to update between go2ts:begin/end markers")
+	mergeByName := flag.Bool("merge-by-name", false, "With -merge-into, replace the declarations of the file matched by name instead of the marked region")
+	module := flag.String("module", "", "Fetch Go module path@version via the module proxy; -in is then relative to the module root")
+	interfaceUnions := flag.Bool("interface-unions", false, "Emit interfaces as unions of the structs implementing them")
+	diagnostics := flag.Bool("diagnostics", false, "Print lossy conversions (any fallbacks, coerced map keys) to stderr")
+	debug := flag.Bool("debug", false, "Print every recursive type conversion, indented by depth, to stderr")
+	varStructs := flag.Bool("var-structs", false, "Also convert anonymous struct types of top-level var/const declarations")
+	recursive := flag.Bool("recursive", true, "Also scan the subdirectories of -in; -recursive=false only reads the .go files directly in it")
+	allDirs := flag.Bool("all-dirs", false, "Also scan vendor, node_modules, testdata and dot-prefixed subdirectories")
+	localTypes := flag.Bool("local-types", false, "Also convert struct types declared inside function bodies")
+	provenance := flag.Bool("provenance", false, "Prefix each interface with a comment naming its Go source position")
+	oneOfUnions := flag.Bool("oneof-unions", false, "Emit structs with a string discriminant and exclusive struct pointers as discriminated unions")
+	oneOfDiscriminant := flag.String("oneof-discriminant", "Type", "Go field name of the discriminant used by -oneof-unions")
+	typeGuards := flag.Bool("type-guards", false, "Emit an isUnionVariant type guard for every variant of -oneof-unions")
+	urlAsObject := flag.Bool("url-object", false, "Map url.URL to its encoding/json object shape instead of string")
+	forceModule := flag.Bool("force-module", false, "End the output with \"export {};\" so TypeScript always treats it as a module (always with -declare-global)")
+	declareGlobal := flag.Bool("declare-global", false, "Wrap the output in \"declare global { ... }\" for ambient use")
+	anyAsUnknown := flag.Bool("unknown", false, "Emit unknown instead of any where a type cannot be converted")
+	maxAny := flag.Int("max-any", -1, "Fail when more than this many fields are typed any; negative is unlimited")
+	strict := flag.Bool("strict", false, "Fail when a field references a type that is not declared in the scanned files")
+	packageConfig := flag.String("package-config", "", "JSON file of per-package -unknown, -strict and -pointers settings, keyed by import path")
+	readonlyArrays := flag.Bool("readonly-arrays", false, "Emit every slice as \"readonly T[]\"")
+	errorAsString := flag.Bool("error-string", false, "Map error to string, for APIs that marshal errors as their message, instead of Error")
+	timeAsDate := flag.Bool("time-date", false, "Map time.Time to Date instead of string, for clients reviving timestamps")
+	preserveAliases := flag.Bool("preserve-aliases", false, "Reference named types such as \"type Email string\" by name instead of their underlying type")
+	runeAsString := flag.Bool("rune-string", false, "Map rune to string instead of number")
+	runeSliceAsString := flag.Bool("rune-slice-string", false, "Map []rune to string instead of number[]")
+	sets := flag.String("sets", "map", "Emit map[K]struct{} as \"map\" ({ [key: K]: any }), \"set\" (Set<K>) or \"record\" ({ [key: K]: true })")
+	byteArraysAsNumbers := flag.Bool("byte-arrays-as-numbers", false, "Emit fixed byte arrays such as [32]byte as number[], as encoding/json writes them, instead of string")
+	jsonMapKeys := flag.Bool("json-map-keys", false, "Emit integer map keys as string, as JSON object keys are, instead of number")
+	embed := flag.String("embed", "omit", "Embedded structs: \"omit\" (extends Omit<Base, ...> on conflicts), \"extends\" (extends when free of conflicts, flattened otherwise) or \"flatten\"")
+	sortFields := flag.Bool("sort-fields", false, "Order the properties of each interface alphabetically instead of in declaration order")
+	pointers := flag.String("pointers", "nullable", "Pointer fields: \"nullable\" (x: T | null), \"optional\" (x?: T) or \"optional-nullable\" (x?: T | null)")
+	tsEnums := flag.Bool("ts-enums", false, "Emit enums as TypeScript enums, e.g. enum OrderStatus { Pending = 0 }, instead of type aliases")
+	stringEnumUnions := flag.Bool("string-enum-unions", false, "Emit enums of string values as unions of their values, e.g. \"red\" | \"green\"")
+	enumValues := flag.Bool("enum-values", false, "Emit a typed array of the values of every enum, e.g. orderStatuses")
+	enumLabels := flag.Bool("enum-labels", false, "Emit a <Enum>Labels record of display labels next to every enum")
+	endpoints := flag.Bool("endpoints", false, "Emit a FooEndpoint type for every FooRequest/FooResponse struct pair")
+	requestSuffix := flag.String("request-suffix", "Request", "Struct name suffix of endpoint requests for -endpoints")
+	responseSuffix := flag.String("response-suffix", "Response", "Struct name suffix of endpoint responses for -endpoints")
+	registry := flag.String("registry", "", "Name of a union of every generated struct, emitted with a <name>Name union of their names")
+	typePrefix := flag.String("prefix", "", "Prefix added to every generated type name")
+	typeSuffix := flag.String("suffix", "", "Suffix added to every generated type name")
+	tagKeys := flag.String("tag-keys", "json", "Comma-separated struct tag keys to take property names from, in priority order")
+	examples := flag.Bool("examples", false, "Document properties with the value of their example struct tag as @example JSDoc")
+	comments := flag.Bool("comments", false, "Document types with their Go doc comments and properties with the doc and line comments of their fields as JSDoc")
+	fieldCase := flag.String("field-case", "go", "Case of property names taken from Go field names: \"go\", \"camel\" (userId) or \"camel-acronyms\" (userID)")
+	acronyms := flag.String("acronyms", "", "Comma-separated acronyms recognized by -field-case (default: ID, URL, URI, API, HTTP, HTTPS, UUID, JSON, XML, SQL, IP)")
+	overrides := flag.String("override", "", "Comma-separated goType=tsType mappings taking precedence over the built-in ones, e.g. \"money.Amount=string,geo.Point=GeoPoint\"")
+	skipTypes := flag.String("skip-types", "", "Comma-separated Go types whose fields are omitted, in addition to context.Context, sync.Mutex, ...")
+	noMkdir := flag.Bool("no-mkdir", false, "Fail when the output directory does not exist instead of creating it")
+	goManifest := flag.String("go-manifest", "", "Also write a Go file mapping each converted Go type to its TypeScript name, for sync checks")
+	goManifestPackage := flag.String("go-manifest-package", "", "Package of the -go-manifest file (default: the package of the converted types; required when they come from several packages)")
+	mappingReport := flag.String("mapping-report", "", "Write a table of every Go type and the TypeScript type it maps to (.md or .csv)")
+	singleQuote := flag.Bool("single-quote", false, "Emit string literals with single quotes instead of double quotes")
+	target := flag.String("target", "ts", "Output: \"ts\" for TypeScript or \"openapi\" for an OpenAPI 3.1 components.schemas fragment in JSON")
+	outputFormat := flag.String("output-format", "default", "Output formatting: \"default\" or \"prettier\" for the built-in prettier-style formatter")
+	emit := flag.String("emit", "all", "Declarations to write: \"all\", \"types\" (interfaces and type aliases) or \"values\" (enum labels and type guards)")
+	typesImport := flag.String("types-import", "./types", "Module the -emit values output imports the types from")
+	separator := flag.String("separator", "semicolon", "Separator of object type members: \"semicolon\", \"comma\" or \"none\" (interface properties only)")
+	bannerFile := flag.String("banner-file", "", "File whose content is prepended verbatim to the output, e.g. a license header or /* eslint-disable */")
+	headerVersion := flag.Bool("header-version", false, "Include the go2ts version in the generated header")
+	showVersion := flag.Bool("version", false, "Print the go2ts version and exit")
 	flag.Parse()
 
-	if _, err := os.Stat(*inputDir); os.IsNotExist(err) {
-		log.Fatalf("Input directory does not exist: %s\n", *inputDir)
+	if *showVersion {
+		fmt.Println(go2ts.Version)
+		return
 	}
 
-	if err := go2ts.Convert(*inputDir, *outputFile); err != nil {
+	inputDirs := strings.Split(*inputDir, ",")
+	for i, in := range inputDirs {
+		inputDirs[i] = strings.TrimSpace(in)
+	}
+	if *module == "" {
+		for i, in := range inputDirs {
+			if go2ts.IsImportPath(in) {
+				dir, err := go2ts.PackageDir(in)
+				if err != nil {
+					log.Fatalf("Input is neither a directory nor a resolvable package: %v\n", err)
+				}
+				inputDirs[i] = dir
+			}
+			if _, err := os.Stat(inputDirs[i]); os.IsNotExist(err) {
+				log.Fatalf("Input directory does not exist: %s\n", inputDirs[i])
+			}
+		}
+	} else if len(inputDirs) > 1 {
+		log.Fatalf("-module takes a single -in directory, got %s\n", *inputDir)
+	}
+
+	var opts go2ts.Options
+	opts.InterfaceUnions = *interfaceUnions
+	opts.VarStructs = *varStructs
+	opts.LocalTypes = *localTypes
+	opts.AllDirs = *allDirs
+	opts.NonRecursive = !*recursive
+	opts.Provenance = *provenance
+	opts.OneOfUnions = *oneOfUnions
+	opts.OneOfDiscriminant = *oneOfDiscriminant
+	opts.TypeGuards = *typeGuards
+	opts.URLAsObject = *urlAsObject
+	opts.DeclareGlobal = *declareGlobal
+	opts.ForceModule = *forceModule
+	opts.Strict = *strict
+	if *maxAny >= 0 {
+		opts.MaxAny = maxAny
+	}
+	opts.AnyAsUnknown = *anyAsUnknown
+	if *packageConfig != "" {
+		packages, err := go2ts.LoadPackageOptions(*packageConfig)
+		if err != nil {
+			log.Fatalf("Invalid -package-config: %v\n", err)
+		}
+		opts.Packages = packages
+	}
+	opts.ReadonlyArrays = *readonlyArrays
+	opts.RuneAsString = *runeAsString
+	opts.PreserveNamedAliases = *preserveAliases
+	opts.TimeAsDate = *timeAsDate
+	opts.ErrorAsString = *errorAsString
+	opts.RuneSliceAsString = *runeSliceAsString
+	switch *sets {
+	case "map":
+		opts.Sets = go2ts.SetAsMap
+	case "set":
+		opts.Sets = go2ts.SetAsSet
+	case "record":
+		opts.Sets = go2ts.SetAsRecord
+	default:
+		log.Fatalf("Invalid -sets value %q: must be map, set or record\n", *sets)
+	}
+	opts.JSONAccurateMapKeys = *jsonMapKeys
+	opts.ByteArraysAsNumbers = *byteArraysAsNumbers
+	switch *embed {
+	case "omit":
+		opts.EmbedStrategy = go2ts.EmbedExtendsOmit
+	case "extends":
+		opts.EmbedStrategy = go2ts.EmbedExtends
+	case "flatten":
+		opts.EmbedStrategy = go2ts.EmbedFlatten
+	default:
+		log.Fatalf("Invalid -embed value %q: must be omit, extends or flatten\n", *embed)
+	}
+	opts.SortFields = *sortFields
+	switch *pointers {
+	case "nullable":
+		opts.Pointers = go2ts.PointerNullable
+	case "optional":
+		opts.Pointers = go2ts.PointerOptional
+	case "optional-nullable":
+		opts.Pointers = go2ts.PointerOptionalNullable
+	default:
+		log.Fatalf("Invalid -pointers value %q: must be nullable, optional or optional-nullable\n", *pointers)
+	}
+	opts.EnumLabels = *enumLabels
+	opts.EnumValues = *enumValues
+	opts.TSEnums = *tsEnums
+	opts.StringEnumUnions = *stringEnumUnions
+	opts.Endpoints = *endpoints
+	opts.RequestSuffix = *requestSuffix
+	opts.ResponseSuffix = *responseSuffix
+	opts.SingleQuote = *singleQuote
+	opts.Registry = *registry
+	switch *target {
+	case "ts":
+	case "openapi":
+		opts.OpenAPI = true
+	default:
+		log.Fatalf("Invalid -target value %q: must be ts or openapi\n", *target)
+	}
+	switch *outputFormat {
+	case "default":
+	case "prettier":
+		opts.Format = true
+	default:
+		log.Fatalf("Invalid -output-format value %q: must be default or prettier\n", *outputFormat)
+	}
+	switch *emit {
+	case "all":
+		opts.Emit = go2ts.EmitAll
+	case "types":
+		opts.Emit = go2ts.EmitTypes
+	case "values":
+		opts.Emit = go2ts.EmitValues
+	default:
+		log.Fatalf("Invalid -emit value %q: must be all, types or values\n", *emit)
+	}
+	opts.TypesImport = *typesImport
+	switch *separator {
+	case "semicolon":
+		opts.Separator = go2ts.SeparatorSemicolon
+	case "comma":
+		opts.Separator = go2ts.SeparatorComma
+	case "none":
+		opts.Separator = go2ts.SeparatorNone
+	default:
+		log.Fatalf("Invalid -separator value %q: must be semicolon, comma or none\n", *separator)
+	}
+	opts.TypePrefix = *typePrefix
+	opts.TypeSuffix = *typeSuffix
+	opts.TagKeys = strings.Split(*tagKeys, ",")
+	opts.Examples = *examples
+	opts.Comments = *comments
+	switch *fieldCase {
+	case "go":
+		opts.FieldCase = go2ts.CaseGo
+	case "camel":
+		opts.FieldCase = go2ts.CaseCamel
+	case "camel-acronyms":
+		opts.FieldCase = go2ts.CaseCamelAcronyms
+	default:
+		log.Fatalf("Invalid -field-case value %q: must be go, camel or camel-acronyms\n", *fieldCase)
+	}
+	if *acronyms != "" {
+		opts.Acronyms = strings.Split(*acronyms, ",")
+	}
+	opts.MappingReport = *mappingReport
+	opts.GoManifest = *goManifest
+	opts.GoManifestPackage = *goManifestPackage
+	opts.NoCreateDirs = *noMkdir
+	if *overrides != "" {
+		m, err := go2ts.ParseOverrides(*overrides)
+		if err != nil {
+			log.Fatalf("Invalid -override value: %v\n", err)
+		}
+		opts.Overrides = m
+	}
+	if *skipTypes != "" {
+		opts.SkipTypes = strings.Split(*skipTypes, ",")
+	}
+	opts.BannerFile = *bannerFile
+	if *headerVersion {
+		opts.Version = go2ts.Version
+	}
+	if *diagnostics {
+		opts.Report = &go2ts.Report{}
+	}
+	if *debug {
+		opts.Trace = &go2ts.Trace{}
+	}
+	if len(outputFiles) == 0 {
+		outputFiles = stringList{"types.ts"}
+	}
+	if *mergeInto != "" {
+		outputFiles = stringList{*mergeInto}
+		opts.Merge = true
+		opts.MergeByName = *mergeByName
+	}
+
+	var err error
+	switch {
+	case *module != "":
+		subDir := "."
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "in" {
+				subDir = *inputDir
+			}
+		})
+		if *stdout {
+			err = go2ts.ConvertModuleToWriter(*module, subDir, os.Stdout, opts)
+		} else {
+			err = go2ts.ConvertModuleToFiles(*module, subDir, outputFiles, opts)
+		}
+	case *stdout:
+		err = go2ts.ConvertDirsToWriter(inputDirs, os.Stdout, opts)
+	default:
+		err = go2ts.ConvertDirsToFiles(inputDirs, outputFiles, opts)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
+	printDiagnostics(opts.Report)
+	printTrace(opts.Trace)
+}
+
+// stringList is a flag.Value collecting the values of a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func printTrace(trace *go2ts.Trace) {
+	if trace == nil {
+		return
+	}
+	fmt.Fprint(os.Stderr, trace)
+}
+
+func printDiagnostics(report *go2ts.Report) {
+	if report == nil {
+		return
+	}
+	for _, d := range report.Diagnostics {
+		log.Printf("warning: %s", d)
+	}
 }
@@ -0,0 +1,76 @@
+package generator
+
+import "strings"
+
+// FieldCase selects how property names derived from Go field names, those
+// of fields without a name in their tag, are cased.
+type FieldCase int
+
+const (
+	// CaseGo keeps the Go field name, as encoding/json does.
+	CaseGo FieldCase = iota
+	// CaseCamel lower-cases the first word and writes acronyms as words,
+	// e.g. UserID → userId and HTTPStatus → httpStatus.
+	CaseCamel
+	// CaseCamelAcronyms lower-cases the first word and keeps later acronyms
+	// upper case, e.g. UserID → userID and HTTPStatus → httpStatus.
+	CaseCamelAcronyms
+)
+
+// DefaultAcronyms lists the acronyms recognized by the camel cases when
+// Options.Acronyms is empty.
+var DefaultAcronyms = []string{"ID", "URL", "URI", "API", "HTTP", "HTTPS", "UUID", "JSON", "XML", "SQL", "IP"}
+
+// fieldName returns the property name of a Go field name in the configured case.
+func (o *Options) fieldName(name string) string {
+	if o.FieldCase == CaseGo {
+		return name
+	}
+	acronyms := o.Acronyms
+	if len(acronyms) == 0 {
+		acronyms = DefaultAcronyms
+	}
+
+	words := splitAcronyms(name, acronyms)
+	for i, w := range words {
+		switch {
+		case i == 0:
+			words[i] = strings.ToLower(w)
+		case isAcronym(w, acronyms) && o.FieldCase == CaseCamel:
+			words[i] = w[:1] + strings.ToLower(w[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// splitAcronyms splits a CamelCase name into its words like splitWords,
+// keeping the plural of an acronym one word, e.g. "UserIDs" → ["User", "IDs"].
+func splitAcronyms(name string, acronyms []string) []string {
+	return joinSplitPlurals(splitWords(name), acronyms)
+}
+
+// joinSplitPlurals rejoins an acronym that splitWords separated from its
+// plural "s", e.g. ["I", "Ds"] of "IDs".
+func joinSplitPlurals(words, acronyms []string) []string {
+	joined := words[:0:0]
+	for i := 0; i < len(words); i++ {
+		if i+1 < len(words) && isAcronym(words[i]+words[i+1], acronyms) {
+			joined = append(joined, words[i]+words[i+1])
+			i++
+			continue
+		}
+		joined = append(joined, words[i])
+	}
+	return joined
+}
+
+// isAcronym reports whether w is one of acronyms, or its plural.
+func isAcronym(w string, acronyms []string) bool {
+	singular := strings.TrimSuffix(w, "s")
+	for _, a := range acronyms {
+		if w == a || singular == a {
+			return true
+		}
+	}
+	return false
+}
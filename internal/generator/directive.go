@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Field directives, written as "//go2ts:<name>" comments on struct fields.
+const (
+	directiveNullable = "nullable" // force "| null" on the property type
+	directiveNonNull  = "nonnull"  // drop "| null" from the property type
+	directiveExtra    = "extra"    // emit a map field as the index signature of the interface
+)
+
+const nullSuffix = " | null"
+
+// PointerStyle selects how pointer fields, which encoding/json writes as
+// null when nil, are declared.
+type PointerStyle int
+
+const (
+	// PointerNullable declares a nullable property, "x: T | null".
+	PointerNullable PointerStyle = iota
+	// PointerOptional declares an optional property without null, "x?: T",
+	// for APIs that leave nil pointers out instead of sending null.
+	PointerOptional
+	// PointerOptionalNullable declares an optional nullable property,
+	// "x?: T | null", for APIs doing either.
+	PointerOptionalNullable
+)
+
+var pointerStyleNames = []string{"nullable", "optional", "optional-nullable"}
+
+// UnmarshalText reads a PointerStyle by name, e.g. in a package config file:
+// "nullable", "optional" or "optional-nullable".
+func (p *PointerStyle) UnmarshalText(text []byte) error {
+	i := slices.Index(pointerStyleNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("invalid pointer style %q: must be nullable, optional or optional-nullable", text)
+	}
+	*p = PointerStyle(i)
+	return nil
+}
+
+func hasDirective(directives []string, name string) bool {
+	for _, d := range directives {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// makeNullable unions tsType with null unless it already is nullable.
+func makeNullable(tsType string) string {
+	if tsType == "any" || strings.HasSuffix(tsType, nullSuffix) {
+		return tsType
+	}
+	if strings.Contains(tsType, "=>") {
+		tsType = "(" + tsType + ")"
+	}
+	return tsType + nullSuffix
+}
+
+// stripNull removes a trailing "| null" added for pointer types.
+func stripNull(tsType string) string {
+	return strings.TrimSuffix(tsType, nullSuffix)
+}
+
+// indexSignature returns the index signature of a map type converted to
+// "{ [key: K]: V }", e.g. "[key: string]: any", and whether tsType is one.
+func indexSignature(tsType string) (string, bool) {
+	inner, ok := strings.CutPrefix(tsType, "{ [key: ")
+	if !ok || !strings.HasSuffix(inner, " }") {
+		return "", false
+	}
+	return "[key: " + strings.TrimSuffix(inner, " }"), true
+}
+
+// widenIndexValue widens the value type of tsType, a map type converted to
+// "{ [key: K]: V }", to its union with the types of the other properties of
+// the interface: TypeScript requires every property to be assignable to the
+// value type of an index signature (TS2411).
+func widenIndexValue(tsType string, others []string) string {
+	inner, ok := strings.CutPrefix(tsType, "{ [key: ")
+	if !ok || !strings.HasSuffix(inner, " }") {
+		return tsType
+	}
+	key, value, ok := strings.Cut(strings.TrimSuffix(inner, " }"), "]: ")
+	if !ok {
+		return tsType
+	}
+
+	members := []string{value}
+	for _, other := range others {
+		if other == "any" || other == "unknown" {
+			members = []string{other}
+			break
+		}
+		if strings.Contains(other, "=>") {
+			other = "(" + other + ")"
+		}
+		if !slices.Contains(members, other) {
+			members = append(members, other)
+		}
+	}
+	if value == "any" || value == "unknown" {
+		members = []string{value}
+	}
+	return "{ [key: " + key + "]: " + strings.Join(members, " | ") + " }"
+}
@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+// EmbedStrategy selects how the fields of embedded structs, which
+// encoding/json promotes, are declared.
+type EmbedStrategy int
+
+const (
+	// EmbedExtendsOmit extends the interface of every embedded struct,
+	// omitting the properties shadowed by the embedding struct or declared
+	// by several embedded structs: "extends Omit<Base, "id">".
+	EmbedExtendsOmit EmbedStrategy = iota
+	// EmbedExtends extends the interface of an embedded struct without
+	// conflicting properties and flattens the fields of the others.
+	EmbedExtends
+	// EmbedFlatten declares the promoted fields as properties of the
+	// embedding interface, which extends nothing.
+	EmbedFlatten
+)
+
+// scopedField is a field converted in the scope of the struct declaring it:
+// the promoted fields of a generic embedded struct, e.g. Page[User], map its
+// type parameters to the type arguments of the embedding. Fields promoted
+// through an embedded pointer are optional, left out when it is nil.
+type scopedField struct {
+	field            parser.StructField
+	typeParams       []string
+	typeParamMapping map[string]string
+	optional         bool
+}
+
+// embedRef is an embedded type, with the type parameters and mapping of
+// the struct embedding it to convert its type arguments.
+type embedRef struct {
+	goType           string
+	typeParams       []string
+	typeParamMapping map[string]string
+	optional         bool // embedded through a pointer, here or above
+}
+
+// splitEmbeds returns the embedded types of s to extend and those whose
+// fields to flatten, according to the EmbedStrategy.
+func splitEmbeds(s parser.GoStruct, structMap map[string]parser.StructInfo, opts *Options) (extend, flatten []string) {
+	switch opts.EmbedStrategy {
+	case EmbedFlatten:
+		return nil, s.Embeds
+	case EmbedExtends:
+		conflicts := conflictingNames(s, structMap, opts)
+		for _, embed := range s.Embeds {
+			base, _ := parser.SplitGenericType(strings.TrimPrefix(embed, "*"))
+			if slices.ContainsFunc(propertyNames(structMap[base], structMap, opts), func(name string) bool {
+				return conflicts[name]
+			}) {
+				flatten = append(flatten, embed)
+			} else {
+				extend = append(extend, embed)
+			}
+		}
+		return extend, flatten
+	}
+	return s.Embeds, nil
+}
+
+// conflictingNames returns the property names of the embedded structs of s
+// that s declares itself or that several of them declare.
+func conflictingNames(s parser.GoStruct, structMap map[string]parser.StructInfo, opts *Options) map[string]bool {
+	own := map[string]bool{}
+	for _, name := range propertyNames(structInfo(s), structMap, opts) {
+		own[name] = true
+	}
+	promoted := map[string]int{}
+	for _, embed := range s.Embeds {
+		base, _ := parser.SplitGenericType(strings.TrimPrefix(embed, "*"))
+		for _, name := range propertyNames(structMap[base], structMap, opts) {
+			promoted[name]++
+		}
+	}
+	conflicts := map[string]bool{}
+	for name, n := range promoted {
+		conflicts[name] = own[name] || n > 1
+	}
+	return conflicts
+}
+
+// promotedFields returns the fields encoding/json promotes to s from the
+// embedded types embeds, following Go's rules: a shallower field shadows
+// deeper ones, and fields of the same name at the same depth are dropped
+// as ambiguous. taken holds the property names already declared.
+func promotedFields(s parser.GoStruct,
+	embeds []string,
+	taken map[string]bool,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	typeParamMapping map[string]string,
+	opts *Options) []scopedField {
+	var level []embedRef
+	for _, embed := range embeds {
+		level = append(level, embedRef{embed, s.TypeParams, typeParamMapping, strings.HasPrefix(embed, "*")})
+	}
+	visited := map[string]bool{s.Name: true}
+
+	var promoted []scopedField
+	for len(level) > 0 {
+		var candidates []scopedField
+		counts := map[string]int{}
+		var next []embedRef
+		for _, ref := range level {
+			base, args := parser.SplitGenericType(strings.TrimPrefix(ref.goType, "*"))
+			info, ok := structMap[base]
+			if !ok || visited[base] {
+				continue
+			}
+			visited[base] = true
+
+			mapping := typeArgMapping(info, args, ref.typeParams, ref.typeParamMapping, aliasMap, structMap, opts)
+			for _, sf := range propertyFields(base, infoFields(info), info.TypeParams, mapping, ref.optional, aliasMap, structMap, opts) {
+				name, skip := propertyName(sf.field, opts)
+				if skip || taken[name] {
+					continue
+				}
+				counts[name]++
+				candidates = append(candidates, sf)
+			}
+			for _, embed := range info.Embeds {
+				next = append(next, embedRef{embed, info.TypeParams, mapping, ref.optional || strings.HasPrefix(embed, "*")})
+			}
+		}
+
+		for _, c := range candidates {
+			if name, _ := propertyName(c.field, opts); counts[name] == 1 {
+				promoted = append(promoted, c)
+			}
+		}
+		for name := range counts {
+			taken[name] = true
+		}
+		level = next
+	}
+	return promoted
+}
+
+// typeArgMapping maps the type parameters of the generic struct info to the
+// TypeScript types of args, converted in the scope of the struct using it.
+// Missing arguments map to any.
+func typeArgMapping(info parser.StructInfo,
+	args []string,
+	typeParams []string,
+	typeParamMapping map[string]string,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	opts *Options) map[string]string {
+	mapping := map[string]string{}
+	for i, param := range info.TypeParams {
+		mapping[param] = "any"
+		if i < len(args) {
+			mapping[param] = parser.GoTypeToTSTypeWithConfig(args[i],
+				aliasMap,
+				typeParams,
+				structMap,
+				typeParamMapping,
+				map[string]bool{},
+				&opts.Config)
+		}
+	}
+	return mapping
+}
+
+// inlinedFields returns the fields sf stands for: the fields of its struct
+// when it is tagged `json:",inline"` without a name, spliced at the level of
+// the struct declaring it as YAML and some JSON libraries do, and sf itself
+// otherwise. The fields of an inlined pointer are optional.
+func inlinedFields(sf scopedField,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	visited map[string]bool,
+	opts *Options) []scopedField {
+	if tag := ParseJSONTag(sf.field.Tags); !tag.Inline || tag.Name != "" {
+		return []scopedField{sf}
+	}
+	goType, pointer := strings.CutPrefix(sf.field.Type, "*")
+	base, args := parser.SplitGenericType(goType)
+	info, ok := structMap[base]
+	if !ok || visited[base] {
+		return []scopedField{sf}
+	}
+	visited[base] = true
+	defer delete(visited, base)
+
+	mapping := typeArgMapping(info, args, sf.typeParams, sf.typeParamMapping, aliasMap, structMap, opts)
+	var fields []scopedField
+	for _, f := range info.Fields {
+		inner := scopedField{parser.StructField(f), info.TypeParams, mapping, sf.optional || pointer}
+		fields = append(fields, inlinedFields(inner, aliasMap, structMap, visited, opts)...)
+	}
+	return fields
+}
+
+// propertyFields returns the fields declaring the properties of the struct
+// name, scoped by typeParams and typeParamMapping: fields with those of
+// their inline fields spliced in, see inlinedFields. Fields of the struct
+// shadow inlined ones, and the first inlined field of a name wins. Every
+// path emitting the properties of a struct goes through it.
+func propertyFields(name string,
+	fields []parser.StructField,
+	typeParams []string,
+	typeParamMapping map[string]string,
+	optional bool,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	opts *Options) []scopedField {
+	declared := map[string]bool{}
+	for _, f := range fields {
+		if !ParseJSONTag(f.Tags).Inline {
+			name, _ := propertyName(f, opts)
+			declared[name] = true
+		}
+	}
+
+	var scoped []scopedField
+	inlined := map[string]bool{}
+	for _, f := range fields {
+		sf := scopedField{f, typeParams, typeParamMapping, optional}
+		if !ParseJSONTag(f.Tags).Inline {
+			scoped = append(scoped, sf)
+			continue
+		}
+		for _, in := range inlinedFields(sf, aliasMap, structMap, map[string]bool{name: true}, opts) {
+			name, _ := propertyName(in.field, opts)
+			if declared[name] || inlined[name] {
+				continue
+			}
+			inlined[name] = true
+			scoped = append(scoped, in)
+		}
+	}
+	return scoped
+}
+
+// propertyNames returns the property names of the struct info, those of its
+// inline fields included, leaving out skipped fields.
+func propertyNames(info parser.StructInfo, structMap map[string]parser.StructInfo, opts *Options) []string {
+	quiet := opts.quiet()
+	var names []string
+	for _, sf := range propertyFields(info.Name, infoFields(info), info.TypeParams, nil, false, nil, structMap, quiet) {
+		if name, skip := propertyName(sf.field, quiet); !skip {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// infoFields returns the fields of info as struct fields.
+func infoFields(info parser.StructInfo) []parser.StructField {
+	fields := make([]parser.StructField, len(info.Fields))
+	for i, f := range info.Fields {
+		fields[i] = parser.StructField(f)
+	}
+	return fields
+}
+
+// quiet returns a copy of o converting types without recording
+// diagnostics, mappings or fields typed any, for types converted again.
+func (o *Options) quiet() *Options {
+	quiet := *o
+	quiet.Report, quiet.Trace, quiet.mappings, quiet.MaxAny = nil, nil, nil, nil
+	return &quiet
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Emit selects which declarations are written to the output.
+type Emit int
+
+const (
+	// EmitAll writes types and values together.
+	EmitAll Emit = iota
+	// EmitTypes writes only the types: interfaces and type aliases.
+	EmitTypes
+	// EmitValues writes only the values, enum label objects and type
+	// guards, importing the types they reference.
+	EmitValues
+)
+
+// defaultTypesImport is the module EmitValues imports the types from.
+const defaultTypesImport = "./types"
+
+// valueDecls collects the value declarations of the output. With EmitAll
+// they are written in place among the types.
+type valueDecls struct {
+	opts  *Options
+	types *strings.Builder
+	sb    strings.Builder
+	refs  []string        // type names the values reference
+	enums map[string]bool // referenced names declared as TypeScript enums
+}
+
+// add writes the declaration decl referencing the type name.
+func (v *valueDecls) add(decl, name string) {
+	if v.opts.Emit == EmitAll {
+		v.types.WriteString(decl)
+		return
+	}
+	v.sb.WriteString(decl)
+	if !slices.Contains(v.refs, name) {
+		v.refs = append(v.refs, name)
+	}
+}
+
+// String returns the value declarations, preceded by a type-only import of
+// the types they reference. TypeScript enums, being values, are imported
+// as such.
+func (v *valueDecls) String() string {
+	if len(v.refs) == 0 {
+		return v.sb.String()
+	}
+	from := v.opts.TypesImport
+	if from == "" {
+		from = defaultTypesImport
+	}
+	refs := slices.Clone(v.refs)
+	slices.Sort(refs)
+	if len(v.enums) == 0 {
+		return fmt.Sprintf("import type { %s } from %s;\n\n", strings.Join(refs, ", "), v.opts.quote(from)) + v.sb.String()
+	}
+	for i, ref := range refs {
+		if !v.enums[ref] {
+			refs[i] = "type " + ref
+		}
+	}
+	return fmt.Sprintf("import { %s } from %s;\n\n", strings.Join(refs, ", "), v.opts.quote(from)) + v.sb.String()
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+const (
+	defaultRequestSuffix  = "Request"
+	defaultResponseSuffix = "Response"
+)
+
+// generateEndpointsTS pairs the non-generic structs named <Name><RequestSuffix>
+// and <Name><ResponseSuffix>, in request declaration order, and renders a
+// helper type for each pair:
+//
+//	export type FooEndpoint = { request: FooRequest; response: FooResponse };
+func generateEndpointsTS(structs []parser.GoStruct, opts *Options) string {
+	reqSuffix, respSuffix := opts.RequestSuffix, opts.ResponseSuffix
+	if reqSuffix == "" {
+		reqSuffix = defaultRequestSuffix
+	}
+	if respSuffix == "" {
+		respSuffix = defaultResponseSuffix
+	}
+
+	responses := map[string]bool{}
+	for _, s := range structs {
+		if len(s.TypeParams) == 0 {
+			responses[s.Name] = true
+		}
+	}
+
+	var sb strings.Builder
+	for _, s := range structs {
+		name, ok := strings.CutSuffix(s.Name, reqSuffix)
+		if !ok || name == "" || len(s.TypeParams) > 0 || !responses[name+respSuffix] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("export type %s = %s;\n\n", opts.typeName(name+"Endpoint"),
+			opts.separateMembers(fmt.Sprintf("{ request: %s; response: %s }", opts.typeName(s.Name), opts.typeName(name+respSuffix)))))
+	}
+	return sb.String()
+}
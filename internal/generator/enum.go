@@ -0,0 +1,248 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+// generateEnumTS renders e as a TypeScript enum, its members named like
+// the labels of generateEnumLabelsTS:
+//
+//	export enum OrderStatus {
+//	  Pending = 0,
+//	  Processing = 1,
+//	}
+func generateEnumTS(e parser.GoEnum, opts *Options) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("export enum %s {\n", opts.typeName(e.Name)))
+	for i, member := range tsEnumMemberNames(e) {
+		sb.WriteString(fmt.Sprintf("  %s = %s,\n", member, opts.literal(e.Members[i].Value)))
+	}
+	sb.WriteString("}\n\n")
+	return sb.String()
+}
+
+// tsEnum reports whether e is emitted as a TypeScript enum: with TSEnums,
+// unless it has no members or a value that is neither a number nor a
+// string, which TypeScript enums cannot hold.
+func (o *Options) tsEnum(e parser.GoEnum) bool {
+	if !o.TSEnums || len(e.Members) == 0 || o.stringUnion(e) {
+		return false
+	}
+	for _, m := range e.Members {
+		if !isEnumValue(m.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// stringUnion reports whether e is emitted as a union of its values: with
+// StringEnumUnions, when every value is a string.
+func (o *Options) stringUnion(e parser.GoEnum) bool {
+	if !o.StringEnumUnions || len(e.Members) == 0 {
+		return false
+	}
+	for _, m := range e.Members {
+		if !strings.HasPrefix(m.Value, `"`) {
+			return false
+		}
+	}
+	return true
+}
+
+// generateEnumUnionTS renders e as a union of its values in declaration
+// order, without duplicates:
+//
+//	export type Color = "red" | "green";
+func generateEnumUnionTS(e parser.GoEnum, opts *Options) string {
+	var values []string
+	for _, m := range e.Members {
+		if value := opts.literal(m.Value); !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return fmt.Sprintf("export type %s = %s;\n\n", opts.typeName(e.Name), strings.Join(values, " | "))
+}
+
+// tsEnumMemberNames returns the member names of e in its TypeScript enum:
+// the names of enumMemberNames, or the constant names where these are not
+// identifiers, e.g. "1" of Level1.
+func tsEnumMemberNames(e parser.GoEnum) []string {
+	names := enumMemberNames(e)
+	for i, name := range names {
+		if !token.IsIdentifier(name) {
+			names[i] = e.Members[i].Name
+		}
+	}
+	return names
+}
+
+// isEnumValue reports whether the literal value is a number or a string.
+func isEnumValue(value string) bool {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	_, err := strconv.Unquote(value)
+	return err == nil
+}
+
+// generateEnumLabelsTS renders a label for every member of e, keyed by value:
+//
+//	export const OrderStatusLabels: Record<OrderStatus, string> = {
+//	  0: "Pending",
+//	  1: "Processing",
+//	};
+//
+// Members sharing a value with an earlier member are left out.
+func generateEnumLabelsTS(e parser.GoEnum, opts *Options) string {
+	label := opts.EnumLabel
+	if label == nil {
+		label = DefaultEnumLabel
+	}
+
+	var sb strings.Builder
+	name := opts.typeName(e.Name)
+	sb.WriteString(fmt.Sprintf("export const %sLabels: Record<%s, string> = {\n", name, name))
+	seen := map[string]bool{}
+	tsMembers := tsEnumMemberNames(e)
+	for i, member := range enumMemberNames(e) {
+		value := e.Members[i].Value
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		key := opts.literalKey(value)
+		if opts.tsEnum(e) {
+			key = "[" + name + "." + tsMembers[i] + "]"
+		}
+		sb.WriteString(fmt.Sprintf("  %s: %s,\n", key, opts.quote(label(member))))
+	}
+	sb.WriteString("};\n\n")
+	return sb.String()
+}
+
+// generateEnumValuesTS renders the values of the members of e, in order and
+// without duplicates, as a typed constant array:
+//
+//	export const orderStatuses = [0, 1] as const satisfies readonly OrderStatus[];
+func generateEnumValuesTS(e parser.GoEnum, opts *Options) string {
+	name := opts.typeName(e.Name)
+	var values []string
+	seen := map[string]bool{}
+	tsMembers := tsEnumMemberNames(e)
+	for i, m := range e.Members {
+		if seen[m.Value] {
+			continue
+		}
+		seen[m.Value] = true
+		if opts.tsEnum(e) {
+			values = append(values, name+"."+tsMembers[i])
+		} else {
+			values = append(values, opts.literal(m.Value))
+		}
+	}
+	return fmt.Sprintf("export const %s = [%s] as const satisfies readonly %s[];\n\n",
+		pluralVarName(name), strings.Join(values, ", "), name)
+}
+
+// pluralVarName returns the lower camel case plural of a type name, e.g.
+// "orderStatuses" for OrderStatus and "categories" for Category.
+func pluralVarName(name string) string {
+	name = strings.ToLower(name[:1]) + name[1:]
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// enumMemberNames returns the constant names of e without the prefix they
+// share: the enum name when every constant starts with it (StatusActive of
+// Status), otherwise the words common to all of them (OrderPending and
+// OrderShipped of OrderStatus).
+func enumMemberNames(e parser.GoEnum) []string {
+	names := make([]string, len(e.Members))
+	for i, m := range e.Members {
+		names[i] = m.Name
+	}
+
+	prefix := e.Name
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || name == prefix {
+			prefix = commonWordPrefix(names)
+			break
+		}
+	}
+	for i, name := range names {
+		names[i] = strings.TrimPrefix(name, prefix)
+	}
+	return names
+}
+
+// commonWordPrefix returns the leading CamelCase words shared by every name,
+// never the whole of a name. It is empty for fewer than two names.
+func commonWordPrefix(names []string) string {
+	const minNames = 2
+	if len(names) < minNames {
+		return ""
+	}
+
+	words := splitWords(names[0])
+	prefix := ""
+	for _, w := range words[:len(words)-1] {
+		candidate := prefix + w
+		for _, name := range names[1:] {
+			if !strings.HasPrefix(name, candidate) || name == candidate ||
+				!isWordStart(name, len(candidate)) {
+				return prefix
+			}
+		}
+		prefix = candidate
+	}
+	return prefix
+}
+
+// DefaultEnumLabel - returns name with spaces inserted at case boundaries,
+// e.g. "CreditCard" → "Credit Card" and "HTTPError" → "HTTP Error".
+func DefaultEnumLabel(name string) string {
+	return strings.Join(splitWords(name), " ")
+}
+
+// splitWords splits a CamelCase name into its words.
+func splitWords(name string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(name); i++ {
+		if isWordStart(name, i) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	return append(words, name[start:])
+}
+
+// isWordStart reports whether a new CamelCase word starts at byte i of name.
+func isWordStart(name string, i int) bool {
+	if i <= 0 || i >= len(name) {
+		return false
+	}
+	prev, cur := rune(name[i-1]), rune(name[i])
+	switch {
+	case cur == '_' || prev == '_':
+		return false
+	case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+		return true
+	case unicode.IsUpper(cur) && unicode.IsUpper(prev) && i+1 < len(name):
+		return unicode.IsLower(rune(name[i+1]))
+	}
+	return false
+}
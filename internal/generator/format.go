@@ -0,0 +1,178 @@
+package generator
+
+import "strings"
+
+const (
+	printWidth = 80   // lines longer than this have their inline objects broken up
+	indentUnit = "  " // one level of indentation
+)
+
+// formatTS normalizes generated TypeScript: the lines of a block are indented
+// one level deeper than the line opening it, properties end with end,
+// blank lines are collapsed to one and never open or close a block, and the
+// inline object types of lines longer than printWidth are broken up, one
+// property per line. It is not a full formatter, but the result is
+// deterministic and formatting it again leaves it unchanged.
+func formatTS(src, end string) string {
+	var out []string
+	var openers []int // indentation of the lines opening the enclosing blocks
+	blank := false
+	for _, raw := range strings.Split(src, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			blank = len(out) > 0
+			continue
+		}
+
+		indent := 0
+		closes := strings.HasPrefix(line, "}")
+		if n := len(openers); n > 0 {
+			indent = openers[n-1] + 1
+			if closes {
+				indent--
+			}
+		}
+		if blank && !closes && !strings.HasSuffix(out[len(out)-1], "{") {
+			out = append(out, "")
+		}
+		blank = false
+
+		if len(openers) > 0 && needsSemicolon(line) {
+			line += end
+		}
+		if strings.HasPrefix(line, "|") || strings.HasPrefix(line, "*") {
+			indent++ // union members and JSDoc continuation lines
+		}
+		out = append(out, wrapLine(strings.Repeat(indentUnit, indent), line, end)...)
+
+		scanCode(line, func(_ int, c byte) {
+			switch {
+			case c == '{':
+				openers = append(openers, indent)
+			case c == '}' && len(openers) > 0:
+				openers = openers[:len(openers)-1]
+			}
+		})
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// needsSemicolon reports whether line, inside a block, is a member lacking
+// its terminating semicolon.
+func needsSemicolon(line string) bool {
+	if strings.HasPrefix(line, "}") || strings.HasPrefix(line, "|") ||
+		strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*") {
+		return false
+	}
+	last := line[len(line)-1]
+	return !strings.ContainsRune("{([,;=", rune(last))
+}
+
+// wrapLine returns line at indent, breaking the first inline object type of
+// a line longer than printWidth into one line per property, each ending with
+// end. Function signatures are left whole.
+func wrapLine(indent, line, end string) []string {
+	if len(indent)+len(line) <= printWidth || strings.HasPrefix(line, "export function ") {
+		return []string{indent + line}
+	}
+	open, closing := inlineObject(line)
+	if open < 0 {
+		return []string{indent + line}
+	}
+
+	lines := []string{indent + strings.TrimRight(line[:open+1], " ")}
+	for _, member := range splitMembers(line[open+1 : closing]) {
+		lines = append(lines, wrapLine(indent+indentUnit, member+end, end)...)
+	}
+	return append(lines, indent+line[closing:])
+}
+
+// inlineObject returns the positions of the braces of the first object type
+// opened and closed on line, or -1 when there is none.
+func inlineObject(line string) (open, closing int) {
+	open, depth := -1, 0
+	scanCode(line, func(i int, c byte) {
+		switch c {
+		case '{':
+			if depth == 0 && open < 0 {
+				open = i
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && open >= 0 && closing == 0 {
+				closing = i
+			}
+		}
+	})
+	if open < 0 || closing == 0 {
+		return -1, -1
+	}
+	return open, closing
+}
+
+// splitMembers splits the body of an object type at its top-level semicolons
+// and commas.
+func splitMembers(body string) []string {
+	var members []string
+	depth, start := 0, 0
+	scanCode(body, func(i int, c byte) {
+		switch c {
+		case '{', '(', '[', '<':
+			depth++
+		case '}', ')', ']':
+			depth--
+		case '>':
+			if i == 0 || body[i-1] != '=' {
+				depth--
+			}
+		case ';', ',':
+			if depth == 0 {
+				members = append(members, body[start:i])
+				start = i + 1
+			}
+		}
+	})
+	members = append(members, body[start:])
+
+	kept := members[:0]
+	for _, m := range members {
+		if m = strings.TrimSpace(m); m != "" {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// scanCode calls fn for every byte of line outside string literals and
+// comments.
+func scanCode(line string, fn func(i int, c byte)) {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(line) && (line[i+1] == '/' || line[i+1] == '*'):
+			if line[i+1] == '/' {
+				return
+			}
+			end := strings.Index(line[i+2:], "*/")
+			if end < 0 {
+				return
+			}
+			i += end + 3
+		default:
+			fn(i, c)
+		}
+	}
+}
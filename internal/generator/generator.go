@@ -2,10 +2,14 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -29,146 +33,1191 @@ func buildAliasMap(aliases []parser.TypeAlias) map[string]string {
 func buildStructMap(structs []parser.GoStruct) map[string]parser.StructInfo {
 	m := map[string]parser.StructInfo{}
 	for _, s := range structs {
-		fields := make([]parser.FieldInfo, len(s.Fields))
-		for i, f := range s.Fields {
-			fields[i] = parser.FieldInfo(f)
-		}
-		m[s.Name] = parser.StructInfo{
-			Name:       s.Name,
-			TypeParams: s.TypeParams,
-			Fields:     fields,
-		}
+		m[s.Name] = structInfo(s)
 	}
 	return m
 }
 
-func fieldToTS(f parser.StructField,
+func structInfo(s parser.GoStruct) parser.StructInfo {
+	fields := make([]parser.FieldInfo, len(s.Fields))
+	for i, f := range s.Fields {
+		fields[i] = parser.FieldInfo(f)
+	}
+	return parser.StructInfo{
+		Name:       s.Name,
+		TypeParams: s.TypeParams,
+		Fields:     fields,
+		Embeds:     s.Embeds,
+	}
+}
+
+// propertyName returns the property name of a field from the configured tag
+// keys, falling back to its Go name. skip is true for fields tagged "-" and
+// fields of types that cannot be serialized.
+func propertyName(f parser.StructField, opts *Options) (name string, skip bool) {
+	name, skip = ExtractFieldName(f.Tags, opts.tagKeys())
+	if name == "" {
+		name = opts.fieldName(f.Name)
+	}
+	return name, skip || opts.unserializable(f.Type)
+}
+
+// sortFields returns fields ordered by property name when SortFields is set,
+// and in declaration order otherwise.
+func sortFields(fields []scopedField, opts *Options) []scopedField {
+	if !opts.SortFields {
+		return fields
+	}
+	sorted := slices.Clone(fields)
+	slices.SortStableFunc(sorted, func(a, b scopedField) int {
+		nameA, _ := propertyName(a.field, opts)
+		nameB, _ := propertyName(b.field, opts)
+		return strings.Compare(nameA, nameB)
+	})
+	return sorted
+}
+
+// DefaultSkipTypes lists the standard library types that hold runtime state
+// rather than data. Struct fields of these types are omitted from the output.
+var DefaultSkipTypes = []string{
+	"context.Context",
+	"embed.FS",
+	"io.Reader",
+	"io.ReadCloser",
+	"io.Writer",
+	"io.WriteCloser",
+	"sync.Cond",
+	"sync.Map",
+	"sync.Mutex",
+	"sync.Once",
+	"sync.Pool",
+	"sync.RWMutex",
+	"sync.WaitGroup",
+}
+
+// unserializable reports whether goType, or the type it points to, is in
+// DefaultSkipTypes or SkipTypes.
+func (o *Options) unserializable(goType string) bool {
+	goType = strings.TrimLeft(goType, "*")
+	return slices.Contains(DefaultSkipTypes, goType) || slices.Contains(o.SkipTypes, goType)
+}
+
+// property is a resolved interface member.
+type property struct {
+	Name     string
+	Optional bool
+	Type     string
+}
+
+func (p property) String() string {
+	if p.Optional {
+		return p.Name + "?: " + p.Type
+	}
+	return p.Name + ": " + p.Type
+}
+
+// fieldProperty resolves the property name, optionality and TypeScript type of a field.
+// Fields tagged omitempty are optional since encoding/json leaves them out when empty.
+func fieldProperty(f parser.StructField,
 	aliasMap map[string]string,
 	typeParams []string,
 	structMap map[string]parser.StructInfo,
-	typeParamMapping map[string]string) string {
-	fieldName := ExtractJSONTag(f.Tags)
-	if fieldName == "" {
-		fieldName = f.Name
-	}
-
+	typeParamMapping map[string]string,
+	opts *Options) property {
 	emptyGenericMap := map[string]bool{}
-	tsType := parser.GoTypeToTSType(f.Type,
+	tsType := parser.GoTypeToTSTypeWithConfig(f.Type,
 		aliasMap,
 		typeParams,
 		structMap,
 		typeParamMapping,
-		emptyGenericMap)
-	if tsType == "" {
-		tsType = "any"
+		emptyGenericMap,
+		&opts.Config)
+	tsType = opts.Resolved(tsType, f.Type)
+
+	jt := ParseJSONTag(f.Tags)
+	if jt.AsString {
+		tsType = quotedScalar(tsType)
+	}
+	optional := jt.OmitEmpty
+	switch {
+	case hasDirective(f.Directives, directiveNonNull):
+		tsType = stripNull(tsType)
+	case hasDirective(f.Directives, directiveNullable):
+		tsType = makeNullable(tsType)
+	case strings.HasPrefix(f.Type, "*"):
+		switch opts.pointers() {
+		case PointerOptional:
+			tsType = stripNull(tsType)
+			optional = true
+		case PointerOptionalNullable:
+			optional = true
+		}
+	}
+
+	tsType = opts.separateMembers(opts.renameRefs(tsType, typeParams))
+	opts.countAny(f.Type, tsType)
+	if opts.anyAsUnknown() {
+		tsType = anyToUnknown(tsType)
+	}
+	opts.recordMapping(f.Type, tsType)
+	name, _ := propertyName(f, opts)
+	return property{
+		Name:     opts.propertyKey(name),
+		Optional: optional,
+		Type:     tsType,
+	}
+}
+
+func fieldToTS(f parser.StructField,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]parser.StructInfo,
+	typeParamMapping map[string]string,
+	opts *Options) string {
+	return propertyTS(f, fieldProperty(f, aliasMap, typeParams, structMap, typeParamMapping, opts), opts)
+}
+
+// propertyTS returns the interface member line of the property p of field f,
+// with its documentation.
+func propertyTS(f parser.StructField, p property, opts *Options) string {
+	member := p.String()
+	if hasDirective(f.Directives, directiveExtra) {
+		if signature, ok := indexSignature(p.Type); ok {
+			member = signature
+		}
 	}
+	return fieldDoc(f, opts) + fmt.Sprintf("  %s%s\n", member, opts.memberEnd())
+}
 
-	return fmt.Sprintf("  %s: %s;\n", fieldName, tsType)
+// fieldDoc returns the JSDoc comment of a property: the doc and line
+// comments of its field with Comments, and the value of the example key of
+// its tag with Examples, e.g. `example:"42"` gives "  /** @example 42 */".
+// It returns "" when there is nothing to document.
+func fieldDoc(f parser.StructField, opts *Options) string {
+	var lines []string
+	if opts.Comments {
+		for _, text := range []string{f.Doc, f.Comment} {
+			if text != "" {
+				lines = append(lines, strings.Split(text, "\n")...)
+			}
+		}
+	}
+	if opts.Examples {
+		if example, ok := reflect.StructTag(f.Tags).Lookup("example"); ok && example != "" {
+			lines = append(lines, "@example "+example)
+		}
+	}
+	return jsDoc("  ", lines)
+}
+
+// typeDoc returns the JSDoc comment of a declaration documented by the Go
+// doc comment doc with Comments, or "".
+func typeDoc(doc string, opts *Options) string {
+	if !opts.Comments || doc == "" {
+		return ""
+	}
+	return jsDoc("", strings.Split(doc, "\n"))
+}
+
+// jsDoc returns lines as a JSDoc comment indented by indent: on one line
+// for a single line, as a block otherwise. It returns "" without lines.
+func jsDoc(indent string, lines []string) string {
+	switch len(lines) {
+	case 0:
+		return ""
+	case 1:
+		return indent + "/** " + escapeDoc(lines[0]) + " */\n"
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(indent+" * "+escapeDoc(line), " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// escapeDoc keeps text from closing the JSDoc comment holding it.
+func escapeDoc(text string) string {
+	return strings.ReplaceAll(text, "*/", `*\/`)
 }
 
 func generateStructTS(s parser.GoStruct,
 	aliasMap map[string]string,
-	structMap map[string]parser.StructInfo) string {
+	structMap map[string]parser.StructInfo,
+	opts *Options) string {
 	typeParams := s.TypeParams
 	typeParamMapping := map[string]string{}
 	for _, param := range typeParams {
 		typeParamMapping[param] = param
 	}
 
-	typeParamsStr := ""
-	if len(typeParams) > 0 {
-		typeParamsStr = "<" + strings.Join(typeParams, ", ") + ">"
+	typeParamsStr := typeParamList(typeParams, s.Constraints, aliasMap, structMap, opts)
+
+	extendEmbeds, flattenEmbeds := splitEmbeds(s, structMap, opts)
+	extended := s
+	extended.Embeds = extendEmbeds
+	extends := ""
+	if bases := embeddedBases(extended, aliasMap, structMap, typeParamMapping, opts); len(bases) > 0 {
+		extends = " extends " + strings.Join(bases, ", ")
 	}
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("export interface %s%s {\n", s.Name, typeParamsStr))
+	own := propertyFields(s.Name, s.Fields, typeParams, typeParamMapping, false, aliasMap, structMap, opts)
 
-	for _, f := range s.Fields {
-		sb.WriteString(fieldToTS(f, aliasMap, typeParams, structMap, typeParamMapping))
+	var fields []scopedField
+	if len(flattenEmbeds) > 0 {
+		taken := map[string]bool{}
+		for _, sf := range own {
+			name, _ := propertyName(sf.field, opts)
+			taken[name] = true
+		}
+		for _, embed := range extendEmbeds {
+			base, _ := parser.SplitGenericType(strings.TrimPrefix(embed, "*"))
+			for _, name := range propertyNames(structMap[base], structMap, opts) {
+				taken[name] = true
+			}
+		}
+		fields = promotedFields(s, flattenEmbeds, taken, aliasMap, structMap, typeParamMapping, opts)
 	}
+	fields = sortFields(append(fields, own...), opts)
 
-	sb.WriteString("}\n\n")
-	return sb.String()
+	var members []parser.StructField
+	var props []property
+	extra := false
+	for _, sf := range fields {
+		f := sf.field
+		if _, skip := propertyName(f, opts); skip {
+			continue
+		}
+		opts.setScope(s.Name + "." + f.Name)
+		p := fieldProperty(f, aliasMap, sf.typeParams, structMap, sf.typeParamMapping, opts)
+		if sf.optional {
+			p.Optional = true
+		}
+		members = append(members, f)
+		props = append(props, p)
+		extra = extra || hasDirective(f.Directives, directiveExtra)
+	}
+	if extra {
+		widenExtraFields(s, members, props, extendEmbeds, aliasMap, structMap, typeParamMapping, opts)
+	}
+
+	var body strings.Builder
+	for i, f := range members {
+		body.WriteString(propertyTS(f, props[i], opts))
+	}
+
+	decl := fmt.Sprintf("export interface %s%s%s", opts.typeName(s.Name), typeParamsStr, extends)
+	if body.Len() == 0 {
+		return decl + " {}\n\n"
+	}
+	return decl + " {\n" + body.String() + "}\n\n"
+}
+
+// widenExtraFields widens the value type of the map fields of s emitted as
+// index signatures, marked //go2ts:extra, to the types of the other
+// properties of the interface, including those inherited from the embedded
+// structs it extends.
+func widenExtraFields(s parser.GoStruct,
+	members []parser.StructField,
+	props []property,
+	extendEmbeds []string,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	typeParamMapping map[string]string,
+	opts *Options) {
+	var others []string
+	for i, f := range members {
+		if !hasDirective(f.Directives, directiveExtra) {
+			others = append(others, props[i].Type)
+		}
+	}
+	if len(extendEmbeds) > 0 {
+		quiet := opts.quiet()
+		for _, sf := range promotedFields(s, extendEmbeds, map[string]bool{}, aliasMap, structMap, typeParamMapping, quiet) {
+			if _, skip := propertyName(sf.field, quiet); !skip {
+				others = append(others, fieldProperty(sf.field, aliasMap, sf.typeParams, structMap, sf.typeParamMapping, quiet).Type)
+			}
+		}
+	}
+	for i, f := range members {
+		if hasDirective(f.Directives, directiveExtra) {
+			props[i].Type = widenIndexValue(props[i].Type, others)
+		}
+	}
+}
+
+// embeddedBases returns the TypeScript types the interface of s extends: its
+// embedded structs, whose fields encoding/json promotes. Embedded types that
+// are not scanned structs have no declaration to extend and are left out.
+// Properties s declares itself shadow those of the embedded struct, as in Go,
+// and are omitted from the base. So are properties declared by more than one
+// embedded struct, which encoding/json drops as ambiguous. The properties of
+// an embedded pointer are optional, being left out when it is nil.
+func embeddedBases(s parser.GoStruct,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	typeParamMapping map[string]string,
+	opts *Options) []string {
+	own := map[string]bool{}
+	for _, name := range propertyNames(structInfo(s), structMap, opts) {
+		own[name] = true
+	}
+
+	promoted := map[string]int{}
+	for _, embed := range s.Embeds {
+		base, _ := parser.SplitGenericType(strings.TrimPrefix(embed, "*"))
+		for _, name := range propertyNames(structMap[base], structMap, opts) {
+			promoted[name]++
+		}
+	}
+
+	var bases []string
+	for _, embed := range s.Embeds {
+		embed, pointer := strings.CutPrefix(embed, "*")
+		base, _ := parser.SplitGenericType(embed)
+		info, ok := structMap[base]
+		if !ok {
+			continue
+		}
+		opts.setScope(s.Name + "." + embed)
+		tsType := parser.GoTypeToTSTypeWithConfig(embed,
+			aliasMap,
+			s.TypeParams,
+			structMap,
+			typeParamMapping,
+			map[string]bool{},
+			&opts.Config)
+		tsType = opts.renameRefs(tsType, s.TypeParams)
+
+		var shadowed []string
+		for _, name := range propertyNames(info, structMap, opts) {
+			if own[name] || promoted[name] > 1 {
+				shadowed = append(shadowed, opts.quote(name))
+			}
+		}
+		if len(shadowed) > 0 {
+			tsType = fmt.Sprintf("Omit<%s, %s>", tsType, strings.Join(shadowed, " | "))
+		}
+		if pointer {
+			// a nil embedded pointer leaves out all of its fields
+			tsType = "Partial<" + tsType + ">"
+		}
+		bases = append(bases, tsType)
+	}
+	return bases
+}
+
+// typeParamList returns the TypeScript type parameter list of a generic
+// declaration, e.g. "<A, B extends number>", or "" without parameters. A
+// constraint other than any or comparable becomes the bound of its
+// parameter; it may reference the other parameters, as in "S extends E[]".
+func typeParamList(params, constraints []string,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	opts *Options) string {
+	if len(params) == 0 {
+		return ""
+	}
+	mapping := map[string]string{}
+	for _, param := range params {
+		mapping[param] = param
+	}
+
+	list := make([]string, len(params))
+	for i, param := range params {
+		list[i] = param
+		if i >= len(constraints) {
+			continue
+		}
+		switch constraints[i] {
+		case "", "any", "interface{}", "comparable":
+			continue
+		}
+		bound := parser.GoTypeToTSTypeWithConfig(constraints[i], aliasMap, params, structMap, mapping, map[string]bool{}, &opts.Config)
+		bound = opts.Resolved(bound, constraints[i])
+		if bound == "" || bound == "any" {
+			continue
+		}
+		list[i] += " extends " + opts.renameRefs(bound, params)
+	}
+	return "<" + strings.Join(list, ", ") + ">"
 }
 
 func generateAliasTS(alias parser.TypeAlias,
 	aliasMap map[string]string,
-	structMap map[string]parser.StructInfo) string {
+	structMap map[string]parser.StructInfo,
+	opts *Options) string {
 	typeParams := alias.TypeParams
 	typeParamMapping := map[string]string{}
 	for _, param := range typeParams {
 		typeParamMapping[param] = param
 	}
 
+	opts.setScope(alias.Name)
 	tsType := alias.Underlying
 	if tsType == "interface{}" {
 		tsType = "any"
 	} else {
-		tsType = parser.GoTypeToTSType(tsType, aliasMap, typeParams, structMap, typeParamMapping, map[string]bool{})
-		if tsType == "" {
-			tsType = "any"
-		}
+		tsType = parser.GoTypeToTSTypeWithConfig(tsType, aliasMap, typeParams, structMap, typeParamMapping, map[string]bool{}, &opts.Config)
+		tsType = opts.Resolved(tsType, alias.Underlying)
+	}
+	tsType = opts.separateMembers(opts.renameRefs(tsType, typeParams))
+	if opts.anyAsUnknown() {
+		tsType = anyToUnknown(tsType)
+	}
+	opts.recordMapping(alias.Underlying, tsType)
+
+	typeParamsStr := typeParamList(typeParams, alias.Constraints, aliasMap, structMap, opts)
+
+	return fmt.Sprintf("export type %s%s = %s;\n\n", opts.typeName(alias.Name), typeParamsStr, tsType)
+}
+
+// keepNames makes references to names resolve to the names themselves.
+// The map is copied so the caller's Config is never modified.
+func keepNames(cfg *parser.Config, names ...string) {
+	kept := make(map[string]bool, len(cfg.KeepNames)+len(names))
+	for n := range cfg.KeepNames {
+		kept[n] = true
+	}
+	for _, n := range names {
+		kept[n] = true
+	}
+	cfg.KeepNames = kept
+}
+
+// setScope records the declaration being converted, e.g. "User.Email", for
+// diagnostics and the mapping report.
+func (o *Options) setScope(scope string) {
+	o.scope = scope
+	if o.Report != nil {
+		o.Report.Scope = scope
+	}
+	if o.Trace != nil {
+		o.Trace.Scope = scope
+	}
+}
+
+// recordMapping adds a conversion to the mapping report, when one is requested.
+func (o *Options) recordMapping(goType, tsType string) {
+	if o.mappings != nil {
+		o.mappings.add(o.scope, goType, tsType)
+	}
+}
+
+// countAny records the field being converted when MaxAny is set and its
+// TypeScript type contains any.
+func (o *Options) countAny(goType, tsType string) {
+	if o.MaxAny == nil {
+		return
+	}
+	found := false
+	mapTypeIdents(tsType, func(ident string) string {
+		found = found || ident == "any"
+		return ident
+	})
+	if found {
+		o.anyFields = append(o.anyFields, fmt.Sprintf("%s (%s)", o.scope, goType))
+	}
+}
+
+// anyBudgetError lists the fields typed any when there are more than MaxAny.
+func (o *Options) anyBudgetError() error {
+	if o.MaxAny == nil || len(o.anyFields) <= *o.MaxAny {
+		return nil
+	}
+	return fmt.Errorf("%d fields are typed any, more than the budget of %d:\n  %s",
+		len(o.anyFields), *o.MaxAny, strings.Join(o.anyFields, "\n  "))
+}
+
+// Options controls how the TypeScript output is produced.
+type Options struct {
+	// Merge rewrites only the region between BeginMarker and EndMarker in an
+	// existing output file, keeping hand-written content outside of it.
+	Merge bool
+
+	// MergeByName, with Merge, replaces the declarations of the existing file
+	// matched by name instead of the marked region, through
+	// MergeDeclarations, keeping hand-written declarations in between.
+	MergeByName bool
+
+	// EmbedStrategy selects how embedded structs are declared: extended with
+	// Omit for shadowed properties (the default), extended only when free of
+	// conflicts and flattened otherwise, or always flattened.
+	EmbedStrategy EmbedStrategy
+
+	// InterfaceUnions emits a Go interface with methods as a union of the
+	// scanned structs implementing all of its methods.
+	InterfaceUnions bool
+
+	// Provenance prefixes each interface with a "// from: pkg.Type (file.go:line)"
+	// comment pointing back at the Go declaration.
+	Provenance bool
+
+	// OneOfUnions emits structs following the oneof convention (a string
+	// discriminant field plus two or more struct pointer fields, exactly one
+	// of which is set) as a discriminated union keyed by the payload's JSON name.
+	OneOfUnions bool
+
+	// OneOfDiscriminant is the Go name of the discriminant field used by
+	// OneOfUnions. Defaults to "Type".
+	OneOfDiscriminant string
+
+	// TypeGuards emits a type guard next to every discriminated union of
+	// OneOfUnions, one per variant, named after the union and the Go payload
+	// field, e.g. isWebhookFoo. The guards are functions, so they are not
+	// emitted with DeclareGlobal.
+	TypeGuards bool
+
+	// DeclareGlobal wraps the declarations in "declare global { ... }" so the
+	// types are available ambiently without imports.
+	DeclareGlobal bool
+
+	// ForceModule ends the output with "export {};" so TypeScript treats the
+	// file as a module even when it declares nothing exported. The
+	// DeclareGlobal output always ends with it.
+	ForceModule bool
+
+	// Strict fails generation when a field references a type that is not
+	// declared in the scanned files, instead of emitting a dangling name.
+	Strict bool
+
+	// AnyAsUnknown emits unknown wherever a type falls back to any, so the
+	// values must be narrowed before use.
+	AnyAsUnknown bool
+
+	// Packages overrides AnyAsUnknown, Strict and Pointers for the
+	// types declared in a Go package, keyed by import path, e.g.
+	// "github.com/me/api/legacy". See LoadPackageOptions.
+	Packages map[string]PackageOptions
+
+	// SingleQuote emits string literals, such as discriminant and enum
+	// values, with single instead of double quotes.
+	SingleQuote bool
+
+	// Format normalizes the output with a small built-in formatter in the
+	// style of prettier: consistent indentation, trailing semicolons and
+	// long inline object types broken up one property per line.
+	Format bool
+
+	// Separator selects the separator of interface properties and of the
+	// members of inline object types; semicolons by default.
+	Separator Separator
+
+	// OpenAPI writes an OpenAPI 3.1 components.schemas fragment, in JSON,
+	// instead of TypeScript: a schema for every enum and non-generic struct,
+	// referenced with "$ref": "#/components/schemas/<Name>". Merge is ignored.
+	OpenAPI bool
+
+	// Emit selects the declarations written: all of them by default, or
+	// only the types or only the values, for setups such as isolatedModules
+	// that keep them in separate files. The values then import the types
+	// they reference from TypesImport, "./types" when empty.
+	Emit        Emit
+	TypesImport string
+
+	// MaxAny, when set, fails the generation if more than *MaxAny fields
+	// have a type containing any, listing them, so a codebase can lower
+	// the budget as it moves toward full type coverage. Nil is unlimited.
+	MaxAny *int
+
+	// BannerFile names a file whose content, such as a license header or
+	// "/* eslint-disable */", is written verbatim at the top of the
+	// TypeScript output, before the generated-by header.
+	BannerFile string
+
+	// Version, when set, is written into the header comment so a committed
+	// file records which go2ts release produced it.
+	Version string
+
+	// Examples documents each property whose field has an example tag, e.g.
+	// `example:"42"`, with an "@example" JSDoc comment.
+	Examples bool
+
+	// Comments documents each interface and type alias with the doc comment
+	// of its Go type, and each property with the doc comment above its field
+	// and the line comment after it, e.g. "Age int // years", as JSDoc.
+	Comments bool
+
+	// FieldCase cases the property names taken from Go field names, when the
+	// tag names none. Acronyms lists the acronyms the camel cases recognize,
+	// defaulting to DefaultAcronyms.
+	FieldCase FieldCase
+	Acronyms  []string
+
+	// TagKeys lists the struct tag keys a property name is taken from, in
+	// priority order, e.g. ["api", "json"]. Defaults to ["json"].
+	TagKeys []string
+
+	// MappingReport, when set, is the path of a table listing every Go type
+	// encountered and the TypeScript type it was mapped to. The table is
+	// written as CSV when the path ends in ".csv" and as markdown otherwise.
+	MappingReport string
+
+	// GoManifest, when set, is the path of a generated Go file declaring
+	// GoManifestVar, a map from the name of each converted Go type to the
+	// name of its TypeScript declaration, for tooling checking that the
+	// output is regenerated when Go types are added. Its package is
+	// GoManifestPackage, defaulting to the package of the converted types;
+	// it is required when they come from several packages.
+	GoManifest        string
+	GoManifestPackage string
+
+	// SkipTypes lists additional Go types, e.g. "zap.Logger", whose struct
+	// fields are omitted like those of DefaultSkipTypes.
+	SkipTypes []string
+
+	// SortFields orders the properties of each interface alphabetically
+	// instead of in Go declaration order.
+	SortFields bool
+
+	// Pointers selects how pointer fields are declared: nullable by default,
+	// or optional for APIs that leave nil pointers out. The nullable and
+	// nonnull directives take precedence.
+	Pointers PointerStyle
+
+	// TypePrefix and TypeSuffix are added to the name of every emitted type,
+	// at its declaration and at every reference, e.g. "Api" turns UserAccount
+	// into ApiUserAccount.
+	TypePrefix string
+	TypeSuffix string
+
+	// EnumLabels emits a "<Enum>Labels" record next to every enum, mapping each
+	// value to a label derived from its constant name by EnumLabel. The
+	// constant name is passed without the prefix shared by the enum's members,
+	// e.g. "CreditCard" for PaymentCreditCard. Defaults to DefaultEnumLabel.
+	// The records are values, so they are not emitted with DeclareGlobal.
+	EnumLabels bool
+	EnumLabel  func(name string) string
+
+	// EnumValues emits an array of the values of every enum, e.g.
+	// "export const orderStatuses = [0, 1] as const satisfies readonly
+	// OrderStatus[];", to iterate them with their type. Like the labels, the
+	// arrays are values and are not emitted with DeclareGlobal.
+	EnumValues bool
+
+	// TSEnums emits every enum as a TypeScript enum, e.g. "export enum
+	// OrderStatus { Pending = 0, ... }", instead of a type alias of its base
+	// type, members named like the labels of EnumLabels. Enums are values,
+	// so the labels and arrays of values reference their members and are
+	// imported as values with EmitValues.
+	TSEnums bool
+
+	// StringEnumUnions emits every enum of string values, e.g. "type Color
+	// string" with ColorRed = "red", as a union of its values,
+	// "export type Color = "red" | "green";", in declaration order. It takes
+	// precedence over TSEnums, which then only applies to numeric enums.
+	StringEnumUnions bool
+
+	// Endpoints pairs structs by name, FooRequest with FooResponse, and emits
+	// "export type FooEndpoint = { request: FooRequest; response: FooResponse }"
+	// for each pair. RequestSuffix and ResponseSuffix default to "Request"
+	// and "Response".
+	Endpoints      bool
+	RequestSuffix  string
+	ResponseSuffix string
+
+	// Registry, when set, is the name of a union of every non-generic struct,
+	// e.g. "AnyModel", emitted together with "<Registry>Name", the union of
+	// their Go names as string literals.
+	Registry string
+
+	// NoCreateDirs fails when the directory of the output file does not
+	// exist, instead of creating it.
+	NoCreateDirs bool
+
+	// Config controls the Go to TypeScript type mapping.
+	parser.Config
+
+	scope     string
+	pkg       string // import path of the package of the declaration being converted
+	mappings  *mappingTable
+	declared  map[string]bool // names declared in the output, for renameRefs
+	anyFields []string        // fields typed any, counted against MaxAny
+}
+
+// PackageOptions overrides Options for the types declared in one Go package.
+// Nil fields keep the value of Options.
+type PackageOptions struct {
+	AnyAsUnknown *bool         `json:"anyAsUnknown,omitempty"`
+	Strict       *bool         `json:"strict,omitempty"`
+	Pointers     *PointerStyle `json:"pointers,omitempty"`
+}
+
+func (o *Options) anyAsUnknown() bool {
+	if p := o.Packages[o.pkg].AnyAsUnknown; p != nil {
+		return *p
 	}
+	return o.AnyAsUnknown
+}
 
-	typeParamsStr := ""
-	if len(typeParams) > 0 {
-		typeParamsStr = "<" + strings.Join(typeParams, ", ") + ">"
+// LoadPackageOptions reads the PackageOptions of Options.Packages from the
+// JSON file at path, an object keyed by import path:
+//
+//	{
+//	  "github.com/me/api/legacy": {"anyAsUnknown": false, "strict": false},
+//	  "github.com/me/api/core": {"strict": true, "pointers": "optional"}
+//	}
+func LoadPackageOptions(path string) (map[string]PackageOptions, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // path is an explicit user input
+	if err != nil {
+		return nil, err
+	}
+	var packages map[string]PackageOptions
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&packages); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
+	return packages, nil
+}
+
+func (o *Options) pointers() PointerStyle {
+	if p := o.Packages[o.pkg].Pointers; p != nil {
+		return *p
+	}
+	return o.Pointers
+}
+
+// strict reports whether unresolved references of pkg fail generation.
+func (o *Options) strict(pkg string) bool {
+	if p := o.Packages[pkg].Strict; p != nil {
+		return *p
+	}
+	return o.Strict
+}
+
+// anyStrict reports whether any package is generated in strict mode.
+func (o *Options) anyStrict() bool {
+	if o.Strict {
+		return true
+	}
+	for _, p := range o.Packages {
+		if p.Strict != nil && *p.Strict {
+			return true
+		}
+	}
+	return false
+}
 
-	return fmt.Sprintf("export type %s%s = %s;\n\n", alias.Name, typeParamsStr, tsType)
+// anyToUnknown replaces any by unknown in the TypeScript type ts.
+func anyToUnknown(ts string) string {
+	return mapTypeIdents(ts, func(ident string) string {
+		if ident == "any" {
+			return "unknown"
+		}
+		return ident
+	})
 }
 
 // GenerateTypeScript - generates TypeScript type definitions from Go struct data.
 func GenerateTypeScript(data parser.GoFileData, outPath string) error {
+	return GenerateTypeScriptWithOptions(data, outPath, Options{})
+}
+
+// GenerateTypeScriptWithOptions - generates TypeScript type definitions using the given options.
+func GenerateTypeScriptWithOptions(data parser.GoFileData, outPath string, opts Options) error {
+	return GenerateTypeScriptToFiles(data, []string{outPath}, opts)
+}
+
+// GenerateTypeScriptToFiles - generates TypeScript type definitions once and
+// writes them to every path of outPaths, each atomically.
+func GenerateTypeScriptToFiles(data parser.GoFileData, outPaths []string, opts Options) error {
+	var buf bytes.Buffer
+	if err := GenerateTypeScriptToWithOptions(data, &buf, opts); err != nil {
+		return err
+	}
+	for _, outPath := range outPaths {
+		if err := writeOutput(filepath.Clean(outPath), buf.String(), &opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateTypeScriptTo - generates TypeScript type definitions from Go struct
+// data and writes them to w.
+func GenerateTypeScriptTo(data parser.GoFileData, w io.Writer) error {
+	return GenerateTypeScriptToWithOptions(data, w, Options{})
+}
+
+// GenerateTypeScriptToWithOptions - generates TypeScript type definitions using
+// the given options and writes them to w. Merge is ignored, there being no
+// file to merge into; nothing is written when generation fails.
+func GenerateTypeScriptToWithOptions(data parser.GoFileData, w io.Writer, opts Options) error {
+	content, err := generate(data, &opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// generate returns the output for data, after writing the side reports
+// requested by opts. It fails on the checks of Strict and MaxAny.
+func generate(data parser.GoFileData, opts *Options) (string, error) {
+	strict := opts.anyStrict()
+	if strict && opts.Report == nil {
+		opts.Report = &parser.Report{}
+	}
+	if opts.MappingReport != "" {
+		opts.mappings = &mappingTable{}
+	}
+
+	var content string
+	if opts.OpenAPI {
+		var err error
+		if content, err = renderOpenAPI(data, opts); err != nil {
+			return "", err
+		}
+	} else {
+		content = renderTypeScript(data, opts)
+		if opts.BannerFile != "" {
+			banner, err := os.ReadFile(filepath.Clean(opts.BannerFile))
+			if err != nil {
+				return "", fmt.Errorf("failed to read banner file: %w", err)
+			}
+			content = withBanner(string(banner), content)
+		}
+	}
+	if strict {
+		if err := unresolvedError(opts.Report, strictScopes(data, opts)); err != nil {
+			return "", err
+		}
+	}
+	if err := opts.anyBudgetError(); err != nil {
+		return "", err
+	}
+	if opts.mappings != nil {
+		if err := writeMappingReport(opts.MappingReport, opts.mappings); err != nil {
+			return "", err
+		}
+	}
+	if opts.GoManifest != "" {
+		if err := writeGoManifest(opts.GoManifest, data, opts); err != nil {
+			return "", err
+		}
+	}
+	return content, nil
+}
+
+// writeOutput writes content to outPath, merged into the file already there
+// with Merge.
+func writeOutput(outPath, content string, opts *Options) error {
+	if opts.Merge && !opts.OpenAPI {
+		existing, err := os.ReadFile(outPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if opts.MergeByName {
+			content = MergeDeclarations(string(existing), content)
+		} else {
+			if content, err = MergeGenerated(string(existing), content); err != nil {
+				return fmt.Errorf("failed to merge into %s: %w", outPath, err)
+			}
+		}
+	}
+
+	if !opts.NoCreateDirs {
+		if err := os.MkdirAll(filepath.Dir(outPath), 0o750); err != nil {
+			return err
+		}
+	}
+	return writeAtomic(outPath, content)
+}
+
+func renderTypeScript(data parser.GoFileData, opts *Options) string {
 	aliasMap := buildAliasMap(data.Aliases)
 	structMap := buildStructMap(data.Structs)
 
+	var unions map[string][]string
+	if opts.InterfaceUnions {
+		unions = interfaceUnions(data)
+		names := make([]string, 0, len(unions))
+		for name := range unions {
+			names = append(names, name)
+		}
+		keepNames(&opts.Config, names...)
+	}
+
+	// enums are referenced by name, also through pointers and containers,
+	// rather than by their underlying type
+	enumNames := make([]string, 0, len(data.Enums))
+	for _, e := range data.Enums {
+		enumNames = append(enumNames, e.Name)
+	}
+	keepNames(&opts.Config, enumNames...)
+
+	opts.declared = make(map[string]bool, len(data.Structs)+len(data.Aliases))
+	for _, s := range data.Structs {
+		opts.declared[s.Name] = true
+	}
+	for _, alias := range data.Aliases {
+		opts.declared[alias.Name] = true
+	}
+
 	var sb strings.Builder
 	estimatedSize := len(data.Structs)*structEstimatedSize + len(data.Aliases)*aliasEstimatedSize + baseEstimatedSize
 	sb.Grow(estimatedSize)
 
-	now := time.Now().Format("2006-01-02 15:04:05")
-	sb.WriteString(fmt.Sprintf("// Generated by go2ts — %s\n\n", now))
+	values := &valueDecls{opts: opts, types: &sb}
 
 	seenAliases := map[string]bool{}
+	enums := make(map[string]parser.GoEnum, len(data.Enums))
+	for _, e := range data.Enums {
+		enums[e.Name] = e
+	}
 
 	for _, alias := range data.Aliases {
 		if seenAliases[alias.Name] {
 			continue
 		}
 		seenAliases[alias.Name] = true
-		sb.WriteString(generateAliasTS(alias, aliasMap, structMap))
+		opts.pkg = alias.ImportPath
+		sb.WriteString(typeDoc(alias.Doc, opts))
+		if members, ok := unions[alias.Name]; ok {
+			sb.WriteString(fmt.Sprintf("export type %s = %s;\n\n", opts.typeName(alias.Name), opts.renameRefs(strings.Join(members, " | "), nil)))
+			continue
+		}
+		if e, ok := enums[alias.Name]; ok && opts.stringUnion(e) {
+			sb.WriteString(generateEnumUnionTS(e, opts))
+		} else if ok && opts.tsEnum(e) {
+			sb.WriteString(generateEnumTS(e, opts))
+			if values.enums == nil {
+				values.enums = map[string]bool{}
+			}
+			values.enums[opts.typeName(e.Name)] = true
+		} else {
+			sb.WriteString(generateAliasTS(alias, aliasMap, structMap, opts))
+		}
+		if e, ok := enums[alias.Name]; ok && !opts.DeclareGlobal {
+			if opts.EnumLabels {
+				values.add(generateEnumLabelsTS(e, opts), opts.typeName(e.Name))
+			}
+			if opts.EnumValues {
+				values.add(generateEnumValuesTS(e, opts), opts.typeName(e.Name))
+			}
+		}
 	}
 
 	for _, s := range data.Structs {
-		sb.WriteString(generateStructTS(s, aliasMap, structMap))
+		opts.pkg = s.ImportPath
+		if opts.Provenance {
+			sb.WriteString(provenanceComment(s))
+		}
+		if s.Marshaler {
+			sb.WriteString(marshalerComment)
+		}
+		sb.WriteString(typeDoc(s.Doc, opts))
+		if opts.OneOfUnions {
+			if u, ok := detectOneOf(s, aliasMap, structMap, opts); ok {
+				sb.WriteString(generateOneOfTS(s, u, aliasMap, structMap, opts))
+				if opts.TypeGuards && !opts.DeclareGlobal {
+					values.add(generateTypeGuardsTS(s, u, opts), opts.typeName(s.Name))
+				}
+				continue
+			}
+		}
+		sb.WriteString(generateStructTS(s, aliasMap, structMap, opts))
 	}
 
-	outPath = filepath.Clean(outPath)
-	f, err := os.Create(outPath)
-	if err != nil {
-		return err
+	if opts.Endpoints {
+		sb.WriteString(generateEndpointsTS(data.Structs, opts))
+	}
+
+	if opts.Registry != "" {
+		sb.WriteString(generateRegistryTS(data.Structs, opts))
+	}
+
+	body := sb.String()
+	if opts.Emit == EmitValues {
+		body = values.String()
+	}
+	if opts.DeclareGlobal {
+		body = wrapDeclareGlobal(body)
+	} else if opts.ForceModule {
+		body = withModuleTrailer(body)
+	}
+	if opts.Format {
+		body = formatTS(body, opts.memberEnd())
+	}
+
+	return header(opts) + body
+}
+
+func (o *Options) tagKeys() []string {
+	if len(o.TagKeys) == 0 {
+		return []string{"json"}
+	}
+	return o.TagKeys
+}
+
+// header returns the comment written at the top of the output.
+func header(opts *Options) string {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	if opts.Version != "" {
+		return fmt.Sprintf("// Generated by go2ts %s — %s\n\n", opts.Version, now)
+	}
+	return fmt.Sprintf("// Generated by go2ts — %s\n\n", now)
+}
+
+// withBanner prepends banner, ended by a newline, to content.
+func withBanner(banner, content string) string {
+	if banner == "" {
+		return content
+	}
+	if !strings.HasSuffix(banner, "\n") {
+		banner += "\n"
+	}
+	return banner + content
+}
+
+// moduleTrailer makes a file without exports a module.
+const moduleTrailer = "\nexport {};\n"
+
+// withModuleTrailer ends body with moduleTrailer, after one blank line.
+func withModuleTrailer(body string) string {
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return strings.TrimPrefix(moduleTrailer, "\n")
+	}
+	return body + "\n" + moduleTrailer
+}
+
+// wrapDeclareGlobal moves the declarations of body into a "declare global"
+// block. Exports are not permitted inside global augmentations, so they are
+// dropped, and "export {};" keeps the file a module as augmentations require.
+func wrapDeclareGlobal(body string) string {
+	var sb strings.Builder
+	sb.WriteString("declare global {\n")
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		if line == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString("  " + strings.TrimPrefix(line, "export ") + "\n")
 	}
-	defer func() {
-		if cerr := f.Close(); cerr != nil && err == nil {
-			err = cerr
+	sb.WriteString("}\n" + moduleTrailer)
+	return sb.String()
+}
+
+// strictScopes returns whether the declaration of a diagnostic's scope, e.g.
+// "User" of "User.Email", is generated in strict mode.
+func strictScopes(data parser.GoFileData, opts *Options) func(scope string) bool {
+	packages := map[string]string{}
+	for _, alias := range data.Aliases {
+		packages[alias.Name] = alias.ImportPath
+	}
+	for _, s := range data.Structs {
+		packages[s.Name] = s.ImportPath
+	}
+	return func(scope string) bool {
+		name, _, _ := strings.Cut(scope, ".")
+		return opts.strict(packages[name])
+	}
+}
+
+// unresolvedError lists the unresolved references recorded in report for the
+// scopes that are strict, if any.
+func unresolvedError(report *parser.Report, strict func(scope string) bool) error {
+	var lines []string
+	for _, d := range report.Filter(parser.KindUnresolved) {
+		if strict(d.Scope) {
+			lines = append(lines, d.String())
 		}
-	}()
-	_, err = f.WriteString(sb.String())
-	return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unresolved type references:\n  %s", strings.Join(lines, "\n  "))
 }
 
-// ExtractJSONTag - extracts the JSON tag name from a struct field tag.
-func ExtractJSONTag(tag string) string {
-	if tag == "" {
-		return ""
+// marshalerComment warns above the interface of a struct with a custom
+// MarshalJSON method, whose JSON go2ts cannot infer from its fields. The
+// interface is emitted all the same: Config.Overrides only changes the type
+// of the references to the struct, not its declaration.
+const marshalerComment = "// custom MarshalJSON: shape may differ\n"
+
+func provenanceComment(s parser.GoStruct) string {
+	name := s.Name
+	if s.Package != "" {
+		name = s.Package + "." + name
 	}
-	name := reflect.StructTag(tag).Get("json")
-	if name == "" || name == "-" {
-		return ""
+	if !s.Pos.IsValid() {
+		return fmt.Sprintf("// from: %s\n", name)
 	}
-	if comma := strings.IndexByte(name, ','); comma != -1 {
-		name = name[:comma]
+	return fmt.Sprintf("// from: %s (%s:%d)\n", name, filepath.Base(s.Pos.Filename), s.Pos.Line)
+}
+
+// ExtractFieldName - returns the name from the first of keys present in the tag.
+// skip is true when that tag's name is "-", meaning the field is not serialized.
+// An empty name (e.g. `json:",omitempty"`) means the Go field name is used.
+func ExtractFieldName(tag string, keys []string) (name string, skip bool) {
+	for _, key := range keys {
+		value, ok := reflect.StructTag(tag).Lookup(key)
+		if !ok {
+			continue
+		}
+		if value == "-" {
+			return "", true
+		}
+		name, _, _ = strings.Cut(value, ",")
+		return name, false
+	}
+	return "", false
+}
+
+// JSONTag is the parsed json key of a struct tag.
+type JSONTag struct {
+	Name      string // property name, empty when the Go field name is used
+	OmitEmpty bool   // ",omitempty"
+	AsString  bool   // ",string", the value is encoded as a JSON string
+	OmitZero  bool   // ",omitzero"
+	Inline    bool   // ",inline", the fields of the value are spliced into the parent
+	Skip      bool   // the tag is "-", the field is not serialized
+}
+
+// ParseJSONTag - parses the json key of a struct field tag into its name and options.
+// Unknown options are ignored. As with encoding/json, `json:"-,"` names the field "-".
+func ParseJSONTag(tag string) JSONTag {
+	value := reflect.StructTag(tag).Get("json")
+	if value == "-" {
+		return JSONTag{Skip: true}
+	}
+
+	parts := strings.Split(value, ",")
+	jt := JSONTag{Name: parts[0]}
+	for _, opt := range parts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "omitempty":
+			jt.OmitEmpty = true
+		case "string":
+			jt.AsString = true
+		case "omitzero":
+			jt.OmitZero = true
+		case "inline":
+			jt.Inline = true
+		}
+	}
+	return jt
+}
+
+// quotedScalar returns the type of a value of the TypeScript type ts encoded
+// with the ",string" option, which quotes numbers and booleans. Other types
+// ignore the option.
+func quotedScalar(ts string) string {
+	base, null := strings.CutSuffix(ts, nullSuffix)
+	switch base {
+	case "number", "boolean", "string":
+		if null {
+			return "string" + nullSuffix
+		}
+		return "string"
 	}
-	return name
+	return ts
+}
+
+// ExtractJSONTag - extracts the JSON tag name from a struct field tag.
+func ExtractJSONTag(tag string) string {
+	return ParseJSONTag(tag).Name
 }
@@ -1,14 +1,62 @@
 package generator_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/limbicnode/go2ts/internal/generator"
 	"github.com/limbicnode/go2ts/internal/parser"
 )
 
+// generateString runs the generator with opts and returns the written output.
+func generateString(t *testing.T, data parser.GoFileData, opts generator.Options) string {
+	t.Helper()
+	outPath := filepath.Join(t.TempDir(), "types.ts")
+	if err := generator.GenerateTypeScriptWithOptions(data, outPath, opts); err != nil {
+		t.Fatalf("GenerateTypeScriptWithOptions failed: %v", err)
+	}
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(out)
+}
+
+// interfaceBlock returns the "export interface name" declaration from out.
+func interfaceBlock(t *testing.T, out, name string) string {
+	t.Helper()
+	start := strings.Index(out, "export interface "+name+" {")
+	if start < 0 {
+		start = strings.Index(out, "export interface "+name+"<")
+	}
+	if start < 0 {
+		start = strings.Index(out, "export interface "+name+" extends ")
+	}
+	if start < 0 {
+		t.Fatalf("interface %s not found in output:\n%s", name, out)
+	}
+	end := strings.Index(out[start:], "\n}")
+	return out[start : start+end+2]
+}
+
+// generateModel generates TypeScript for the shared test model.
+func generateModel(t *testing.T, opts generator.Options) string {
+	t.Helper()
+	data, err := parser.ParseGoFiles(filepath.Join("..", "..", "test", "testdata", "model"))
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	return generateString(t, data, opts)
+}
+
 func TestGenerateTypeScriptFromModel(t *testing.T) {
 	dir := filepath.Join("..", "..", "test", "testdata", "model")
 
@@ -105,6 +153,33 @@ func TestExtractJSONTag(t *testing.T) {
 	}
 }
 
+func TestParseJSONTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want generator.JSONTag
+	}{
+		{`json:"name"`, generator.JSONTag{Name: "name"}},
+		{`json:"name,omitempty"`, generator.JSONTag{Name: "name", OmitEmpty: true}},
+		{`json:"name,omitempty" xml:"xmlName"`, generator.JSONTag{Name: "name", OmitEmpty: true}},
+		{`json:"id,string"`, generator.JSONTag{Name: "id", AsString: true}},
+		{`json:",omitzero"`, generator.JSONTag{OmitZero: true}},
+		{`json:",inline"`, generator.JSONTag{Inline: true}},
+		{`json:"count,omitempty,string,omitzero"`, generator.JSONTag{Name: "count", OmitEmpty: true, AsString: true, OmitZero: true}},
+		{`json:"name, omitempty"`, generator.JSONTag{Name: "name", OmitEmpty: true}},
+		{`json:"name,unknown"`, generator.JSONTag{Name: "name"}},
+		{`json:"-"`, generator.JSONTag{Skip: true}},
+		{`json:"-,"`, generator.JSONTag{Name: "-"}},
+		{`xml:"xmlName"`, generator.JSONTag{}},
+		{``, generator.JSONTag{}},
+	}
+
+	for _, tt := range tests {
+		if got := generator.ParseJSONTag(tt.tag); got != tt.want {
+			t.Errorf("ParseJSONTag(%q) = %+v; want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
 func TestGenerateTypeScript_MissingBranches(t *testing.T) {
 	data := parser.GoFileData{
 		Aliases: []parser.TypeAlias{
@@ -133,3 +208,2629 @@ func TestGenerateTypeScript_MissingBranches(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestMergeGenerated(t *testing.T) {
+	gen := "export type A = string;\n"
+	block := generator.BeginMarker + "\n" + gen + generator.EndMarker
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{"EmptyFile", "", block + "\n"},
+		{"NoMarkers", "type Manual = number;", "type Manual = number;\n\n" + block + "\n"},
+		{
+			"ReplaceBetweenMarkers",
+			"type Manual = number;\n" + generator.BeginMarker + "\nold\n" + generator.EndMarker + "\n// tail\n",
+			"type Manual = number;\n" + block + "\n// tail\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := generator.MergeGenerated(tt.existing, gen)
+			if err != nil {
+				t.Fatalf("MergeGenerated failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MergeGenerated() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// a begin marker without an end marker is not appended to again
+	_, err := generator.MergeGenerated("type Manual = number;\n"+generator.BeginMarker+"\nold\n", gen)
+	if err == nil || !strings.Contains(err.Error(), "unterminated generated region") || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected an unterminated region error, got %v", err)
+	}
+}
+
+func TestGenerateTypeScript_Merge(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{Name: "User", Fields: []parser.StructField{{Name: "Name", Type: "string", Tags: `json:"name"`}}},
+		},
+	}
+	outPath := filepath.Join(t.TempDir(), "types.ts")
+	manual := "// hand-written\nexport type Helper = string;\n"
+	if err := os.WriteFile(outPath, []byte(manual), 0644); err != nil {
+		t.Fatalf("failed to write existing file: %v", err)
+	}
+
+	opts := generator.Options{Merge: true}
+	for i := 0; i < 2; i++ {
+		if err := generator.GenerateTypeScriptWithOptions(data, outPath, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	got := string(out)
+	if !strings.HasPrefix(got, manual) {
+		t.Errorf("hand-written section was not preserved:\n%s", got)
+	}
+	if strings.Count(got, generator.BeginMarker) != 1 || strings.Count(got, "export interface User") != 1 {
+		t.Errorf("expected a single generated region:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_InterfaceUnions(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "Event", Underlying: "interface{}"},
+			{Name: "Orphan", Underlying: "interface{}"},
+		},
+		Interfaces: []parser.GoInterface{
+			{Name: "Event", Methods: []string{"Process"}},
+			{Name: "Orphan", Methods: []string{"Missing"}},
+		},
+		Structs: []parser.GoStruct{
+			{Name: "FooEvent", Methods: []string{"Process"}},
+			{Name: "Plain"},
+			{Name: "BarEvent", Methods: []string{"Name", "Process"}},
+			{Name: "Generic", TypeParams: []string{"T"}, Methods: []string{"Process"}},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{InterfaceUnions: true})
+	if !strings.Contains(got, "export type Event = FooEvent | BarEvent;") {
+		t.Errorf("expected Event union, got:\n%s", got)
+	}
+	if !strings.Contains(got, "export type Orphan = any;") {
+		t.Errorf("expected interface without implementers to stay any, got:\n%s", got)
+	}
+
+	got = generateString(t, data, generator.Options{})
+	if !strings.Contains(got, "export type Event = any;") {
+		t.Errorf("expected union to be disabled by default, got:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_Report(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name: "MapWithStructPosKey",
+				Fields: []parser.StructField{
+					{Name: "KeyData", Type: "map[struct{ X, Y int }]string"},
+					{Name: "Name", Type: "string"},
+				},
+			},
+		},
+	}
+
+	opts := generator.Options{}
+	opts.Report = &parser.Report{}
+	got := generateString(t, data, opts)
+
+	if !strings.Contains(got, "KeyData: { [key: string]: string };") {
+		t.Errorf("output changed by report:\n%s", got)
+	}
+	want := []parser.Diagnostic{{
+		Kind:    parser.KindLossy,
+		Scope:   "MapWithStructPosKey.KeyData",
+		GoType:  "map[struct{ X, Y int }]string",
+		Message: "map key struct{ X, Y int } coerced to string",
+	}}
+	if len(opts.Report.Diagnostics) != 1 || opts.Report.Diagnostics[0] != want[0] {
+		t.Errorf("Diagnostics = %#v, want %#v", opts.Report.Diagnostics, want)
+	}
+}
+
+func TestGenerateTypeScript_NullabilityDirectives(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name: "Wire",
+				Fields: []parser.StructField{
+					{Name: "Raw", Type: "string", Tags: `json:"raw"`, Directives: []string{"nullable"}},
+					{Name: "Ptr", Type: "*int", Tags: `json:"ptr"`, Directives: []string{"nonnull"}},
+					{Name: "Already", Type: "*string", Tags: `json:"already"`, Directives: []string{"nullable"}},
+					{Name: "Fn", Type: "func", Tags: `json:"fn"`, Directives: []string{"nullable"}},
+					{Name: "Plain", Type: "*int", Tags: `json:"plain"`},
+				},
+			},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{})
+	for _, want := range []string{
+		"  raw: string | null;\n",
+		"  ptr: number;\n",
+		"  already: string | null;\n",
+		"  fn: ((...args: any[]) => any) | null;\n",
+		"  plain: number | null;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_Provenance(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name:    "UserAccount",
+				Package: "model",
+				Pos:     token.Position{Filename: "/src/model/test_struct.go", Line: 78, Column: 6},
+				Fields:  []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}},
+			},
+			{Name: "Synthetic"},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{Provenance: true})
+	if !strings.Contains(got, "// from: model.UserAccount (test_struct.go:78)\nexport interface UserAccount {") {
+		t.Errorf("expected provenance comment, got:\n%s", got)
+	}
+	if !strings.Contains(got, "// from: Synthetic\nexport interface Synthetic {") {
+		t.Errorf("expected provenance without position, got:\n%s", got)
+	}
+
+	if got = generateString(t, data, generator.Options{}); strings.Contains(got, "// from:") {
+		t.Errorf("provenance should be off by default:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_OneOfUnions(t *testing.T) {
+	webhook := parser.GoStruct{
+		Name: "Webhook",
+		Fields: []parser.StructField{
+			{Name: "ID", Type: "string", Tags: `json:"id"`},
+			{Name: "Type", Type: "string", Tags: `json:"type"`},
+			{Name: "Foo", Type: "*FooData", Tags: `json:"foo,omitempty"`},
+			{Name: "Bar", Type: "*BarData", Tags: `json:"bar,omitempty"`},
+			{Name: "Retry", Type: "*int", Tags: `json:"retry"`},
+		},
+	}
+	single := parser.GoStruct{
+		Name: "Single",
+		Fields: []parser.StructField{
+			{Name: "Type", Type: "string"},
+			{Name: "Foo", Type: "*FooData"},
+		},
+	}
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{Name: "FooData", Fields: []parser.StructField{{Name: "A", Type: "int"}}},
+			{Name: "BarData", Fields: []parser.StructField{{Name: "B", Type: "string"}}},
+			webhook,
+			single,
+		},
+	}
+
+	got := generateString(t, data, generator.Options{OneOfUnions: true})
+	want := "export type Webhook =\n" +
+		"  | { type: \"foo\"; foo: FooData; id: string; retry: number | null }\n" +
+		"  | { type: \"bar\"; bar: BarData; id: string; retry: number | null };\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected discriminated union:\n%s\ngot:\n%s", want, got)
+	}
+	if !strings.Contains(got, "export interface Single {") {
+		t.Errorf("struct with a single variant should stay an interface:\n%s", got)
+	}
+
+	got = generateString(t, data, generator.Options{})
+	if !strings.Contains(got, "export interface Webhook {") {
+		t.Errorf("oneof detection should be opt-in:\n%s", got)
+	}
+
+	webhook.Fields[1].Name = "Kind"
+	data.Structs[2] = webhook
+	got = generateString(t, data, generator.Options{OneOfUnions: true, OneOfDiscriminant: "Kind"})
+	if !strings.Contains(got, "export type Webhook =\n") {
+		t.Errorf("expected custom discriminant to be detected:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_DeclareGlobal(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Email", Underlying: "string"}},
+		Structs: []parser.GoStruct{
+			{Name: "User", Fields: []parser.StructField{{Name: "Email", Type: "Email", Tags: `json:"email"`}}},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{DeclareGlobal: true})
+	want := "declare global {\n" +
+		"  type Email = string;\n" +
+		"\n" +
+		"  interface User {\n" +
+		"    email: string;\n" +
+		"  }\n" +
+		"}\n" +
+		"\n" +
+		"export {};\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("unexpected declare global output:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "// Generated by go2ts") {
+		t.Errorf("header should stay outside of the global block:\n%s", got)
+	}
+
+	if got = generateString(t, data, generator.Options{}); strings.Contains(got, "declare global") {
+		t.Errorf("declare global should be off by default:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_GenericContainerField(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type OrderedMap[K comparable, V any] struct {
+	Keys   []K       ` + "`json:\"keys\"`" + `
+	Values map[K]V   ` + "`json:\"values\"`" + `
+}
+
+type Inventory struct {
+	Counts OrderedMap[string, int] ` + "`json:\"counts\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write model.go: %v", err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	got := generateString(t, data, generator.Options{})
+	for _, want := range []string{
+		"export interface OrderedMap<K, V> {\n  keys: K[];\n  values: { [key: string]: V };\n}",
+		"export interface Inventory {\n  counts: OrderedMap<string, number>;\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_UnresolvedReferences(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Email", Underlying: "string"}},
+		Structs: []parser.GoStruct{
+			{Name: "Box", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "V", Type: "T"}}},
+			{
+				Name: "Order",
+				Fields: []parser.StructField{
+					{Name: "Email", Type: "Email"},
+					{Name: "Items", Type: "[]Box[int]"},
+					{Name: "Widget", Type: "*Widget"},
+					{Name: "Gadget", Type: "Holder[Gadget]"},
+				},
+			},
+		},
+	}
+
+	opts := generator.Options{}
+	opts.Report = &parser.Report{}
+	got := generateString(t, data, opts)
+	if !strings.Contains(got, "Widget: Widget | null;") {
+		t.Errorf("unresolved references should be emitted as-is when not strict:\n%s", got)
+	}
+
+	var refs []string
+	for _, d := range opts.Report.Filter(parser.KindUnresolved) {
+		refs = append(refs, d.Scope+" "+d.Message)
+	}
+	want := []string{
+		"Order.Widget unresolved reference to Widget",
+		"Order.Gadget unresolved reference to Holder",
+		"Order.Gadget unresolved reference to Gadget",
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("unresolved = %v, want %v", refs, want)
+	}
+
+	err := generator.GenerateTypeScriptWithOptions(data, filepath.Join(t.TempDir(), "types.ts"), generator.Options{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "Order.Widget (Widget): unresolved reference to Widget") {
+		t.Errorf("expected strict mode error, got %v", err)
+	}
+
+	data.Structs[1].Fields = data.Structs[1].Fields[:2]
+	if err := generator.GenerateTypeScriptWithOptions(data, filepath.Join(t.TempDir(), "types.ts"), generator.Options{Strict: true}); err != nil {
+		t.Errorf("unexpected strict mode error: %v", err)
+	}
+}
+
+func TestGenerateTypeScript_InterfaceUnionReferences(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases:    []parser.TypeAlias{{Name: "Event", Underlying: "interface{}"}},
+		Interfaces: []parser.GoInterface{{Name: "Event", Methods: []string{"Process"}}},
+		Structs: []parser.GoStruct{
+			{Name: "FooEvent", Methods: []string{"Process"}},
+			{
+				Name: "Batch",
+				Fields: []parser.StructField{
+					{Name: "Events", Type: "[]Event", Tags: `json:"events"`},
+					{Name: "ByID", Type: "map[string]Event", Tags: `json:"by_id"`},
+					{Name: "Last", Type: "*Event", Tags: `json:"last"`},
+				},
+			},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{InterfaceUnions: true})
+	for _, want := range []string{
+		"  events: Event[];\n",
+		"  by_id: { [key: string]: Event };\n",
+		"  last: Event | null;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	got = generateString(t, data, generator.Options{})
+	if !strings.Contains(got, "  events: any[];\n") {
+		t.Errorf("without unions interfaces should stay any:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_OmitEmptyCollections(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+
+	tests := []struct {
+		iface string
+		want  []string
+	}{
+		{"NestedBasicInfo", []string{
+			"  basic_info: BasicPersonInfo | null;\n",
+			"  tags: string[];\n",
+			"  metadata?: { [key: string]: any };\n",
+		}},
+		{"UserAccount", []string{
+			"  permissions: string[];\n",
+			"  metadata?: { [key: string]: any };\n",
+			"  profile?: UserProfileDetail | null;\n",
+		}},
+		{"StoreItem", []string{
+			"  tags?: string[];\n",
+			"  attributes?: { [key: string]: string };\n",
+		}},
+	}
+
+	for _, tt := range tests {
+		block := interfaceBlock(t, out, tt.iface)
+		for _, want := range tt.want {
+			if !strings.Contains(block, want) {
+				t.Errorf("%s: expected %q in:\n%s", tt.iface, want, block)
+			}
+		}
+	}
+}
+
+func TestGenerateTypeScript_HeaderVersion(t *testing.T) {
+	got := generateString(t, parser.GoFileData{}, generator.Options{Version: "v1.2.3"})
+	if !strings.HasPrefix(got, "// Generated by go2ts v1.2.3 — ") {
+		t.Errorf("expected version in header, got:\n%s", got)
+	}
+
+	got = generateString(t, parser.GoFileData{}, generator.Options{})
+	if !strings.HasPrefix(got, "// Generated by go2ts — ") {
+		t.Errorf("expected plain header by default, got:\n%s", got)
+	}
+}
+
+func TestExtractFieldName(t *testing.T) {
+	tests := []struct {
+		tag      string
+		keys     []string
+		wantName string
+		wantSkip bool
+	}{
+		{`json:"name"`, []string{"json"}, "name", false},
+		{`api:"publicName" json:"name"`, []string{"api", "json"}, "publicName", false},
+		{`json:"name"`, []string{"api", "json"}, "name", false},
+		{`api:"-" json:"name"`, []string{"api", "json"}, "", true},
+		{`api:"publicName" json:"-"`, []string{"api", "json"}, "publicName", false},
+		{`json:"-"`, []string{"json"}, "", true},
+		{`json:"-,"`, []string{"json"}, "-", false},
+		{`json:",omitempty"`, []string{"json"}, "", false},
+		{`xml:"x"`, []string{"api", "json"}, "", false},
+		{``, []string{"json"}, "", false},
+	}
+
+	for _, tt := range tests {
+		name, skip := generator.ExtractFieldName(tt.tag, tt.keys)
+		if name != tt.wantName || skip != tt.wantSkip {
+			t.Errorf("ExtractFieldName(%q, %v) = (%q, %v), want (%q, %v)",
+				tt.tag, tt.keys, name, skip, tt.wantName, tt.wantSkip)
+		}
+	}
+}
+
+func TestGenerateTypeScript_TagKeys(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name: "Account",
+				Fields: []parser.StructField{
+					{Name: "ID", Type: "int", Tags: `api:"accountId" json:"id"`},
+					{Name: "Email", Type: "string", Tags: `json:"email"`},
+					{Name: "Secret", Type: "string", Tags: `api:"-" json:"secret"`},
+					{Name: "Internal", Type: "string", Tags: `json:"-"`},
+				},
+			},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{TagKeys: []string{"api", "json"}})
+	want := "export interface Account {\n  accountId: number;\n  email: string;\n}"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+
+	got = generateString(t, data, generator.Options{})
+	want = "export interface Account {\n  id: number;\n  email: string;\n  secret: string;\n}"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+}
+
+func TestGenerateTypeScript_MappingReport(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "UserID", Underlying: "int64"}},
+		Structs: []parser.GoStruct{
+			{
+				Name: "User",
+				Fields: []parser.StructField{
+					{Name: "ID", Type: "UserID", Tags: `json:"id"`},
+					{Name: "Tags", Type: "[]string", Tags: `json:"tags"`},
+					{Name: "Name", Type: "string", Tags: `json:"name"`},
+					{Name: "Email", Type: "string", Tags: `json:"email"`},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: "types.md",
+			want: []string{
+				"| Go type | TypeScript type | Used by |\n| --- | --- | --- |\n",
+				"| `UserID` | `number` | User.ID |\n",
+				"| `[]string` | `string[]` | User.Tags |\n",
+				"| `int64` | `number` | UserID |\n",
+				"| `string` | `string` | User.Name, User.Email |\n",
+			},
+		},
+		{
+			file: "types.csv",
+			want: []string{
+				"go_type,ts_type,used_by\n",
+				"string,string,User.Name;User.Email\n",
+				"[]string,string[],User.Tags\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			generateString(t, data, generator.Options{MappingReport: path})
+
+			out, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read mapping report: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("expected %q in mapping report:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_CreatesOutputDir(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{{Name: "User", Fields: []parser.StructField{{Name: "ID", Type: "int"}}}},
+	}
+	outPath := filepath.Join(t.TempDir(), "generated", "api", "types.ts")
+
+	err := generator.GenerateTypeScriptWithOptions(data, outPath, generator.Options{NoCreateDirs: true})
+	if err == nil {
+		t.Fatal("expected error for missing output directory with NoCreateDirs")
+	}
+
+	if err := generator.GenerateTypeScript(data, outPath); err != nil {
+		t.Fatalf("GenerateTypeScript failed: %v", err)
+	}
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(out), "export interface User {") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := generator.GenerateTypeScript(data, filepath.Join(blocker, "types.ts")); err == nil {
+		t.Error("expected error when the output directory cannot be created")
+	}
+}
+
+func TestGenerateTypeScript_SkipTypes(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name: "Job",
+				Fields: []parser.StructField{
+					{Name: "ID", Type: "string", Tags: `json:"id"`},
+					{Name: "Ctx", Type: "context.Context", Tags: `json:"ctx"`},
+					{Name: "Mu", Type: "sync.Mutex"},
+					{Name: "Wg", Type: "*sync.WaitGroup"},
+					{Name: "Logger", Type: "*zap.Logger", Tags: `json:"logger"`},
+				},
+			},
+		},
+	}
+
+	got := generateString(t, data, generator.Options{})
+	want := "export interface Job {\n  id: string;\n  logger: any | null;\n}"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+
+	got = generateString(t, data, generator.Options{SkipTypes: []string{"zap.Logger"}})
+	want = "export interface Job {\n  id: string;\n}"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+}
+
+func TestGenerateTypeScript_AliasOfGenericOverAlias(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+
+	for _, want := range []string{
+		"export type AliasMapResultType = GenericResult<{ [key: string]: string }>;",
+		"export interface ResultWithAliasMap {\n  alias_result: GenericResult<{ [key: string]: string }>;\n}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateTypeScript_SortFields(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{
+				Name: "User",
+				Fields: []parser.StructField{
+					{Name: "Name", Type: "string", Tags: `json:"name"`},
+					{Name: "ID", Type: "int", Tags: `json:"id"`},
+					{Name: "Email", Type: "string", Tags: `json:"email,omitempty"`},
+					{Name: "Age", Type: "int", Tags: `json:"zz_age"`},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want string
+	}{
+		{
+			name: "declaration order by default",
+			want: "export interface User {\n  name: string;\n  id: number;\n  email?: string;\n  zz_age: number;\n}",
+		},
+		{
+			name: "sorted by property name",
+			opts: generator.Options{SortFields: true},
+			want: "export interface User {\n  email?: string;\n  id: number;\n  name: string;\n  zz_age: number;\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateString(t, data, tt.opts)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected %q in output:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_TypePrefixSuffix(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "Status", Underlying: "int", Defined: true},
+			{Name: "Users", Underlying: "[]User"},
+		},
+		Structs: []parser.GoStruct{
+			{
+				Name: "User",
+				Fields: []parser.StructField{
+					{Name: "Status", Type: "Status"},
+					{Name: "Friends", Type: "map[string]*User", Tags: `json:"friends"`},
+					{Name: "Inline", Type: "struct{ User string }", Tags: `json:"inline"`},
+				},
+			},
+			{
+				Name:       "Page",
+				TypeParams: []string{"User"},
+				Fields: []parser.StructField{
+					{Name: "Items", Type: "[]User", Tags: `json:"items"`},
+				},
+			},
+			{
+				Name: "Listing",
+				Fields: []parser.StructField{
+					{Name: "Page", Type: "Page[User]", Tags: `json:"page"`},
+					{Name: "All", Type: "Users", Tags: `json:"all"`},
+				},
+			},
+		},
+	}
+	// keep the alias name so the reference to it is visible
+	var opts generator.Options
+	opts.TypePrefix = "Api"
+	opts.TypeSuffix = "DTO"
+	opts.KeepNames = map[string]bool{"Status": true}
+	got := generateString(t, data, opts)
+
+	for _, want := range []string{
+		"export type ApiStatusDTO = number;",
+		"export type ApiUsersDTO = ApiUserDTO[];",
+		"export interface ApiUserDTO {\n" +
+			"  Status: ApiStatusDTO;\n" +
+			"  friends: { [key: string]: (ApiUserDTO | null) };\n" +
+			"  inline: { User: string };\n}",
+		"export interface ApiPageDTO<User> {\n  items: User[];\n}",
+		"export interface ApiListingDTO {\n  page: ApiPageDTO<ApiUserDTO>;\n  all: ApiUserDTO[];\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_EmbeddedStructs(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+
+	for _, want := range []string{
+		"export interface EmbeddedOnly extends Partial<EmbeddedType> {}\n",
+		"export interface AnonymousEmbeddedBasic extends Partial<BasicPersonInfo> {\n  score: number;\n}",
+		"export interface StructBWithConflict extends Omit<StructAWithField, \"field\"> {\n  field: string;\n}",
+		"export interface EmptyStruct {}\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestDefaultEnumLabel(t *testing.T) {
+	tests := map[string]string{
+		"Active":     "Active",
+		"CreditCard": "Credit Card",
+		"HTTPError":  "HTTP Error",
+		"Level2High": "Level2 High",
+		"snake_case": "snake_case",
+		"":           "",
+	}
+	for name, want := range tests {
+		if got := generator.DefaultEnumLabel(name); got != want {
+			t.Errorf("DefaultEnumLabel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGenerateTypeScript_EnumLabels(t *testing.T) {
+	out := generateModel(t, generator.Options{EnumLabels: true})
+	for _, want := range []string{
+		"export type UserStatus = number;\n\n" +
+			"export const UserStatusLabels: Record<UserStatus, string> = {\n" +
+			"  0: \"Active\",\n  1: \"Inactive\",\n  2: \"Suspended\",\n};\n",
+		"export const OrderStatusLabels: Record<OrderStatus, string> = {\n  0: \"Pending\",\n",
+		"export const PaymentMethodLabels: Record<PaymentMethod, string> = {\n  0: \"Credit Card\",\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Color", Underlying: "string", Defined: true}},
+		Enums: []parser.GoEnum{{Name: "Color", BaseType: "string", Members: []parser.EnumMember{
+			{Name: "ColorRed", Value: `"red"`},
+			{Name: "ColorCrimson", Value: `"red"`},
+			{Name: "ColorDarkBlue", Value: `"blue"`},
+		}}},
+	}
+	got := generateString(t, data, generator.Options{EnumLabels: true, EnumLabel: strings.ToLower})
+	want := "export const ColorLabels: Record<Color, string> = {\n  \"red\": \"red\",\n  \"blue\": \"darkblue\",\n};\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+
+	// negative numbers are not valid keys of an object literal
+	data = parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Code", Underlying: "int", Defined: true}},
+		Enums: []parser.GoEnum{{Name: "Code", BaseType: "int", Members: []parser.EnumMember{
+			{Name: "CodeNeg", Value: "-1"},
+			{Name: "CodeZero", Value: "0"},
+		}}},
+	}
+	got = generateString(t, data, generator.Options{EnumLabels: true})
+	want = "export const CodeLabels: Record<Code, string> = {\n  [-1]: \"Neg\",\n  0: \"Zero\",\n};\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+
+	if got := generateModel(t, generator.Options{}); strings.Contains(got, "Labels") {
+		t.Errorf("labels emitted without EnumLabels:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_Endpoints(t *testing.T) {
+	field := []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}}
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{Name: "GetUserRequest", Fields: field},
+			{Name: "GetUserResponse", Fields: field},
+			{Name: "ListUsersReq", Fields: field},
+			{Name: "ListUsersResp", Fields: field},
+			{Name: "OrphanRequest", Fields: field},
+			{Name: "Request", Fields: field},
+			{Name: "Response", Fields: field},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		opts    generator.Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "disabled by default",
+			notWant: []string{"Endpoint"},
+		},
+		{
+			name:    "default suffixes",
+			opts:    generator.Options{Endpoints: true},
+			want:    []string{"export type GetUserEndpoint = { request: GetUserRequest; response: GetUserResponse };\n"},
+			notWant: []string{"OrphanEndpoint", "ListUsersEndpoint", "type Endpoint"},
+		},
+		{
+			name:    "custom suffixes",
+			opts:    generator.Options{Endpoints: true, RequestSuffix: "Req", ResponseSuffix: "Resp"},
+			want:    []string{"export type ListUsersEndpoint = { request: ListUsersReq; response: ListUsersResp };\n"},
+			notWant: []string{"GetUserEndpoint"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateString(t, data, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in output:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("unexpected %q in output:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_PackageOptions(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Payload", Underlying: "interface{}", Package: "model", ImportPath: "example.com/legacy/model"}},
+		Structs: []parser.GoStruct{
+			{
+				Name:       "Order",
+				Package:    "model",
+				ImportPath: "example.com/core/model",
+				Fields: []parser.StructField{
+					{Name: "Meta", Type: "map[string]interface{}", Tags: `json:"meta"`},
+					{Name: "Inline", Type: "struct{ any string }", Tags: `json:"inline"`},
+					{Name: "Note", Type: "*string", Tags: `json:"note"`},
+				},
+			},
+			{
+				Name:       "LegacyOrder",
+				Package:    "model",
+				ImportPath: "example.com/legacy/model",
+				Fields: []parser.StructField{
+					{Name: "Meta", Type: "map[string]interface{}", Tags: `json:"meta"`},
+					{Name: "Ext", Type: "Missing", Tags: `json:"ext"`},
+					{Name: "Note", Type: "*string", Tags: `json:"note"`},
+				},
+			},
+		},
+	}
+	yes, no := true, false
+	optional := generator.PointerOptional
+
+	got := generateString(t, data, generator.Options{
+		AnyAsUnknown: true,
+		Packages: map[string]generator.PackageOptions{
+			"example.com/legacy/model": {AnyAsUnknown: &no},
+			"example.com/core/model":   {Pointers: &optional},
+		},
+	})
+	for _, want := range []string{
+		"export type Payload = any;",
+		"export interface Order {\n  meta: { [key: string]: unknown };\n  inline: { any: string };\n  note?: string;\n}",
+		"export interface LegacyOrder {\n  meta: { [key: string]: any };\n  ext: Missing;\n  note: string | null;\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	// only the strict package fails on its unresolved reference
+	outPath := filepath.Join(t.TempDir(), "types.ts")
+	opts := generator.Options{Packages: map[string]generator.PackageOptions{"example.com/core/model": {Strict: &yes}}}
+	if err := generator.GenerateTypeScriptWithOptions(data, outPath, opts); err != nil {
+		t.Errorf("unexpected error for non-strict package: %v", err)
+	}
+	opts = generator.Options{Strict: true, Packages: map[string]generator.PackageOptions{"example.com/core/model": {Strict: &yes}}}
+	err := generator.GenerateTypeScriptWithOptions(data, outPath, opts)
+	if err == nil || !strings.Contains(err.Error(), "LegacyOrder.Ext") {
+		t.Errorf("expected unresolved error for LegacyOrder.Ext, got %v", err)
+	}
+	opts = generator.Options{Strict: true, Packages: map[string]generator.PackageOptions{"example.com/legacy/model": {Strict: &no}}}
+	if err := generator.GenerateTypeScriptWithOptions(data, outPath, opts); err != nil {
+		t.Errorf("unexpected error with strict disabled for legacy: %v", err)
+	}
+
+	// the options are read from a JSON file keyed by import path
+	path := filepath.Join(t.TempDir(), "packages.json")
+	config := `{"example.com/legacy/model": {"anyAsUnknown": false, "strict": false}, "example.com/core/model": {"pointers": "optional"}}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	packages, err := generator.LoadPackageOptions(path)
+	if err != nil {
+		t.Fatalf("LoadPackageOptions failed: %v", err)
+	}
+	want := map[string]generator.PackageOptions{
+		"example.com/legacy/model": {AnyAsUnknown: &no, Strict: &no},
+		"example.com/core/model":   {Pointers: &optional},
+	}
+	if !reflect.DeepEqual(packages, want) {
+		t.Errorf("LoadPackageOptions = %+v, want %+v", packages, want)
+	}
+	for _, bad := range []string{
+		`{"example.com/core/model": {"nullable": true}}`,
+		`{"example.com/core/model": {"pointers": "null"}}`,
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := generator.LoadPackageOptions(path); err == nil {
+			t.Errorf("LoadPackageOptions(%s): expected an error", bad)
+		}
+	}
+}
+
+func TestGenerateTypeScript_UnresolvableTypes(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Broken", Underlying: ""}},
+		Structs: []parser.GoStruct{
+			{
+				Name: "Holder",
+				Fields: []parser.StructField{
+					{Name: "Bad", Type: "", Tags: `json:"bad"`},
+					{Name: "BadPtr", Type: "*", Tags: `json:"bad_ptr"`},
+					{Name: "BadMap", Type: "map[string]", Tags: `json:"bad_map"`},
+				},
+			},
+		},
+	}
+
+	var opts generator.Options
+	opts.Report = &parser.Report{}
+	got := generateString(t, data, opts)
+	for _, want := range []string{
+		"export type Broken = never;",
+		"export interface Holder {\n  bad: never;\n  bad_ptr: never | null;\n  bad_map: { [key: string]: never };\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if n := len(opts.Report.Filter(parser.KindLossy)); n != 4 {
+		t.Errorf("expected 4 lossy diagnostics, got %d: %v", n, opts.Report.Diagnostics)
+	}
+}
+
+func TestGenerateTypeScript_TimePointerField(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+	want := "export interface MongoDBDataModel {\n  id: string;\n  created_at: string;\n  updated_at?: string | null;\n}"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output:\n%s", want, out)
+	}
+}
+
+func TestGenerateTypeScript_ResultWithMap(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+	want := "export interface ResultWithMap {\n  mapping: GenericResult<{ [key: string]: (UserProfileDetail | null) }>;\n}"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output:\n%s", want, out)
+	}
+}
+
+func TestGenerateTypeScript_SingleQuote(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Mood", Underlying: "string", Defined: true}},
+		Enums: []parser.GoEnum{{Name: "Mood", BaseType: "string", Members: []parser.EnumMember{
+			{Name: "MoodHappy", Value: `"happy"`},
+			{Name: "MoodQuoted", Value: `"it's \"fine\""`},
+		}}},
+		Structs: []parser.GoStruct{
+			{Name: "Base", Fields: []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}}},
+			{Name: "Child", Embeds: []string{"Base"}, Fields: []parser.StructField{{Name: "ID", Type: "string", Tags: `json:"id"`}}},
+		},
+	}
+	label := func(name string) string {
+		if name == "Quoted" {
+			return "It's"
+		}
+		return name
+	}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want []string
+	}{
+		{
+			name: "double quotes by default",
+			opts: generator.Options{EnumLabels: true, EnumLabel: label},
+			want: []string{
+				"  \"happy\": \"Happy\",\n  \"it's \\\"fine\\\"\": \"It's\",\n",
+				"extends Omit<Base, \"id\">",
+			},
+		},
+		{
+			name: "single quotes",
+			opts: generator.Options{EnumLabels: true, EnumLabel: label, SingleQuote: true},
+			want: []string{
+				"  'happy': 'Happy',\n  'it\\'s \"fine\"': 'It\\'s',\n",
+				"extends Omit<Base, 'id'>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateString(t, data, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in output:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_OptionalPointers(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{
+		Name: "Profile",
+		Fields: []parser.StructField{
+			{Name: "Nickname", Type: "*string", Tags: `json:"nickname"`},
+			{Name: "Avatar", Type: "*string", Tags: `json:"avatar,omitempty"`},
+			{Name: "Bio", Type: "*string", Tags: `json:"bio"`, Directives: []string{"nullable"}},
+			{Name: "Name", Type: "string", Tags: `json:"name"`},
+		},
+	}}}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want string
+	}{
+		{
+			// nullable, or optional and nullable with omitempty
+			name: "nullable by default",
+			want: "export interface Profile {\n" +
+				"  nickname: string | null;\n" +
+				"  avatar?: string | null;\n" +
+				"  bio: string | null;\n" +
+				"  name: string;\n}",
+		},
+		{
+			name: "optional pointers",
+			opts: generator.Options{Pointers: generator.PointerOptional},
+			want: "export interface Profile {\n" +
+				"  nickname?: string;\n" +
+				"  avatar?: string;\n" +
+				"  bio: string | null;\n" +
+				"  name: string;\n}",
+		},
+		{
+			name: "optional nullable pointers",
+			opts: generator.Options{Pointers: generator.PointerOptionalNullable},
+			want: "export interface Profile {\n" +
+				"  nickname?: string | null;\n" +
+				"  avatar?: string | null;\n" +
+				"  bio: string | null;\n" +
+				"  name: string;\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interfaceBlock(t, generateString(t, data, tt.opts), "Profile")
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_SelfReferentialGenerics(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{Name: "Tree", TypeParams: []string{"T"}, Fields: []parser.StructField{
+				{Name: "Value", Type: "T", Tags: `json:"value"`},
+				{Name: "Children", Type: "[]Tree[T]", Tags: `json:"children"`},
+				{Name: "Parent", Type: "*Tree[T]", Tags: `json:"parent"`},
+				{Name: "Index", Type: "map[string]*Tree[T]", Tags: `json:"index"`},
+				{Name: "Forest", Type: "Forest[T]", Tags: `json:"forest"`},
+			}},
+			{Name: "IntTree", Fields: []parser.StructField{
+				{Name: "Root", Type: "Tree[int]", Tags: `json:"root"`},
+			}},
+		},
+		Aliases: []parser.TypeAlias{
+			{Name: "Forest", TypeParams: []string{"T"}, Underlying: "[]Tree[T]", Defined: true},
+		},
+	}
+	report := &parser.Report{}
+	out := generateString(t, data, generator.Options{Config: parser.Config{Report: report}})
+
+	want := "export interface Tree<T> {\n" +
+		"  value: T;\n" +
+		"  children: Tree<T>[];\n" +
+		"  parent: Tree<T> | null;\n" +
+		"  index: { [key: string]: (Tree<T> | null) };\n" +
+		"  forest: Forest<T>;\n}"
+	if got := interfaceBlock(t, out, "Tree"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	for _, want := range []string{"export type Forest<T> = Tree<T>[];", "  root: Tree<number>;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if len(report.Diagnostics) > 0 {
+		t.Errorf("unexpected diagnostics: %v", report.Diagnostics)
+	}
+}
+
+func TestGenerateTypeScript_FieldCase(t *testing.T) {
+	names := []string{"ID", "UserID", "HTTPStatus", "APIKey", "UserIDs", "HTTPSProxyURL", "DisplayName"}
+	var fields []parser.StructField
+	for _, name := range names {
+		fields = append(fields, parser.StructField{Name: name, Type: "string"})
+	}
+	fields = append(fields, parser.StructField{Name: "OwnerID", Type: "string", Tags: `json:"OwnerID"`})
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "Account", Fields: fields}}}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want []string
+	}{
+		{
+			name: "go names by default",
+			want: []string{"ID", "UserID", "HTTPStatus", "APIKey", "UserIDs", "HTTPSProxyURL", "DisplayName", "OwnerID"},
+		},
+		{
+			name: "camel",
+			opts: generator.Options{FieldCase: generator.CaseCamel},
+			want: []string{"id", "userId", "httpStatus", "apiKey", "userIds", "httpsProxyUrl", "displayName", "OwnerID"},
+		},
+		{
+			name: "camel keeping acronyms",
+			opts: generator.Options{FieldCase: generator.CaseCamelAcronyms},
+			want: []string{"id", "userID", "httpStatus", "apiKey", "userIDs", "httpsProxyURL", "displayName", "OwnerID"},
+		},
+		{
+			// words missing from the list keep their Go case after the first word
+			name: "custom acronyms",
+			opts: generator.Options{FieldCase: generator.CaseCamel, Acronyms: []string{"ID", "HTTP"}},
+			want: []string{"id", "userId", "httpStatus", "apiKey", "userIds", "httpsProxyURL", "displayName", "OwnerID"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "export interface Account {\n"
+			for _, name := range tt.want {
+				want += "  " + name + ": string;\n"
+			}
+			want += "}"
+			if got := interfaceBlock(t, generateString(t, data, tt.opts), "Account"); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_TypeGuards(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "FooData", Fields: []parser.StructField{{Name: "A", Type: "int"}}},
+		{Name: "BarData", Fields: []parser.StructField{{Name: "B", Type: "string"}}},
+		{Name: "Webhook", Fields: []parser.StructField{
+			{Name: "Type", Type: "string", Tags: `json:"type"`},
+			{Name: "Foo", Type: "*FooData", Tags: `json:"foo,omitempty"`},
+			{Name: "Bar", Type: "*BarData", Tags: `json:"bar,omitempty"`},
+		}},
+	}}
+
+	got := generateString(t, data, generator.Options{OneOfUnions: true, TypeGuards: true})
+	want := "export function isWebhookFoo(v: Webhook): v is Extract<Webhook, { type: \"foo\" }> {\n" +
+		"  return v.type === \"foo\";\n}\n\n" +
+		"export function isWebhookBar(v: Webhook): v is Extract<Webhook, { type: \"bar\" }> {\n" +
+		"  return v.type === \"bar\";\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected type guards:\n%s\ngot:\n%s", want, got)
+	}
+
+	// the guard takes the renamed type and quotes a discriminant that is not
+	// an identifier
+	data.Structs[2].Fields[0].Tags = `json:"x-kind"`
+	got = generateString(t, data, generator.Options{OneOfUnions: true, TypeGuards: true, TypePrefix: "Api"})
+	want = "export function isApiWebhookFoo(v: ApiWebhook): v is Extract<ApiWebhook, { \"x-kind\": \"foo\" }> {\n" +
+		"  return v[\"x-kind\"] === \"foo\";\n}\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected type guards:\n%s\ngot:\n%s", want, got)
+	}
+	if !strings.Contains(got, `| { "x-kind": "foo"; foo: ApiFooData`) {
+		t.Errorf("expected a quoted discriminant in the union:\n%s", got)
+	}
+
+	for _, opts := range []generator.Options{
+		{OneOfUnions: true},
+		{OneOfUnions: true, TypeGuards: true, DeclareGlobal: true},
+	} {
+		if got := generateString(t, data, opts); strings.Contains(got, "function") {
+			t.Errorf("unexpected type guards with %+v:\n%s", opts, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_PointerToGeneric(t *testing.T) {
+	got := interfaceBlock(t, generateModel(t, generator.Options{}), "ResultWithPointerAndList")
+	want := "export interface ResultWithPointerAndList {\n" +
+		"  data: GenericResult<UserAccount | null>;\n" +
+		"  list: GenericResult<(UserProfileDetail | null)[]>;\n" +
+		"  ptr_data: GenericResult<UserAccount | null> | null;\n" +
+		"  ptr_list: GenericResult<(UserProfileDetail | null)[]> | null;\n}"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_EmbeddedFieldConflicts(t *testing.T) {
+	got := interfaceBlock(t, generateModel(t, generator.Options{}), "StructBWithConflict")
+	if n := strings.Count(got, "field"); n != 2 { // the Omit key and the own property
+		t.Errorf("expected a single field property, got:\n%s", got)
+	}
+	if !strings.Contains(got, "  field: string;\n") {
+		t.Errorf("expected the outer field to win, got:\n%s", got)
+	}
+
+	// a name promoted by two embedded structs at the same depth is ambiguous
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Audit", Fields: []parser.StructField{
+			{Name: "ID", Type: "int", Tags: `json:"id"`},
+			{Name: "At", Type: "string", Tags: `json:"at"`},
+		}},
+		{Name: "Owner", Fields: []parser.StructField{
+			{Name: "ID", Type: "string", Tags: `json:"id"`},
+			{Name: "Name", Type: "string", Tags: `json:"name"`},
+		}},
+		{Name: "Document", Embeds: []string{"Audit", "*Owner"}, Fields: []parser.StructField{
+			{Name: "Title", Type: "string", Tags: `json:"title"`},
+		}},
+	}}
+	want := "export interface Document extends Omit<Audit, \"id\">, Partial<Omit<Owner, \"id\">> {\n  title: string;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Document"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_Examples(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "Order", Fields: []parser.StructField{
+		{Name: "ID", Type: "int", Tags: `json:"id" example:"42"`},
+		{Name: "Email", Type: "string", Tags: `json:"email" example:"jane@example.com"`},
+		{Name: "Note", Type: "string", Tags: `json:"note" example:"ends */ early"`},
+		{Name: "Total", Type: "float64", Tags: `json:"total"`},
+	}}}}
+
+	want := "export interface Order {\n" +
+		"  /** @example 42 */\n" +
+		"  id: number;\n" +
+		"  /** @example jane@example.com */\n" +
+		"  email: string;\n" +
+		"  /** @example ends *\\/ early */\n" +
+		"  note: string;\n" +
+		"  total: number;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{Examples: true}), "Order"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "@example") {
+		t.Errorf("examples should be opt-in:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_Registry(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "UserAccount", Fields: []parser.StructField{{Name: "ID", Type: "int"}}},
+		{Name: "Page", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "Items", Type: "[]T"}}},
+		{Name: "SalesOrder", Fields: []parser.StructField{{Name: "ID", Type: "int"}}},
+	}}
+
+	got := generateString(t, data, generator.Options{Registry: "AnyModel"})
+	want := "export type AnyModel = UserAccount | SalesOrder;\n\n" +
+		"export type AnyModelName = \"UserAccount\" | \"SalesOrder\";\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected registry:\n%s\ngot:\n%s", want, got)
+	}
+
+	got = generateString(t, data, generator.Options{Registry: "AnyModel", TypePrefix: "Api"})
+	if !strings.Contains(got, "export type AnyModel = ApiUserAccount | ApiSalesOrder;\n") {
+		t.Errorf("expected prefixed registry members:\n%s", got)
+	}
+
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "AnyModel") {
+		t.Errorf("registry should be opt-in:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_AnyCollections(t *testing.T) {
+	var fields []parser.StructField
+	goTypes := []string{"[]interface{}", "[]any", "[][]interface{}", "map[string][]interface{}", "map[string]any", "*[]any"}
+	for i, goType := range goTypes {
+		fields = append(fields, parser.StructField{Name: fmt.Sprintf("F%d", i), Type: goType})
+	}
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "Bag", Fields: fields}}}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want []string
+	}{
+		{
+			name: "any",
+			want: []string{"any[]", "any[]", "any[][]", "{ [key: string]: any[] }", "{ [key: string]: any }", "any[] | null"},
+		},
+		{
+			name: "unknown",
+			opts: generator.Options{AnyAsUnknown: true},
+			want: []string{"unknown[]", "unknown[]", "unknown[][]", "{ [key: string]: unknown[] }", "{ [key: string]: unknown }", "unknown[] | null"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "export interface Bag {\n"
+			for i, ts := range tt.want {
+				want += fmt.Sprintf("  F%d: %s;\n", i, ts)
+			}
+			want += "}"
+			if got := interfaceBlock(t, generateString(t, data, tt.opts), "Bag"); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the tests")
+
+func TestGenerateTypeScript_FormatGolden(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "FooData", Fields: []parser.StructField{{Name: "A", Type: "int"}}},
+		{Name: "BarData", Fields: []parser.StructField{{Name: "B", Type: "string"}}},
+		{Name: "Webhook", Fields: []parser.StructField{
+			{Name: "Type", Type: "string", Tags: `json:"type"`},
+			{Name: "Foo", Type: "*FooData", Tags: `json:"foo"`},
+			{Name: "Bar", Type: "*BarData", Tags: `json:"bar"`},
+			{Name: "DeliveryAttempts", Type: "int", Tags: `json:"delivery_attempts"`},
+			{Name: "Endpoint", Type: "url.URL", Tags: `json:"endpoint"`},
+		}},
+		{Name: "Settings", Fields: []parser.StructField{{
+			Name: "Limits",
+			Type: "struct{ MaxConnections int; MaxRequestsPerSecond int; Retry struct{ Attempts int; BackoffMilliseconds int; Jitter bool } }",
+			Tags: `json:"limits" example:"{\"MaxConnections\": 8}"`,
+		}}},
+	}}
+	opts := generator.Options{
+		Format:      true,
+		OneOfUnions: true,
+		TypeGuards:  true,
+		Examples:    true,
+		Config:      parser.Config{URLAsObject: true},
+	}
+
+	tests := []struct {
+		golden string
+		opts   generator.Options
+	}{
+		{"format.ts", opts},
+		{"format_global.ts", generator.Options{Format: true, DeclareGlobal: true, Config: parser.Config{URLAsObject: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			out := generateString(t, data, tt.opts)
+			_, got, _ := strings.Cut(out, "\n") // drop the timestamped header
+
+			path := filepath.Join("..", "..", "test", "testdata", "golden", tt.golden)
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_Separator(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Point", Underlying: "struct{ X int; Y int }", Defined: true}},
+		Structs: []parser.GoStruct{{
+			Name: "Shape",
+			Fields: []parser.StructField{
+				{Name: "Name", Type: "string", Tags: `json:"name"`},
+				{Name: "Size", Type: "struct{ W int; H int }", Tags: `json:"size"`},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		sep  generator.Separator
+		want []string
+	}{
+		{
+			name: "semicolons by default",
+			sep:  generator.SeparatorSemicolon,
+			want: []string{
+				"  name: string;\n  size: { W: number; H: number };\n}",
+				"export type Point = { X: number; Y: number };",
+			},
+		},
+		{
+			name: "commas",
+			sep:  generator.SeparatorComma,
+			want: []string{
+				"  name: string,\n  size: { W: number, H: number },\n}",
+				"export type Point = { X: number, Y: number };",
+			},
+		},
+		{
+			name: "none",
+			sep:  generator.SeparatorNone,
+			want: []string{
+				"  name: string\n  size: { W: number; H: number }\n}",
+				"export type Point = { X: number; Y: number };",
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, format := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/format=%v", tt.name, format), func(t *testing.T) {
+				got := generateString(t, data, generator.Options{Separator: tt.sep, Format: format})
+				for _, want := range tt.want {
+					if !strings.Contains(got, want) {
+						t.Errorf("expected %q in output:\n%s", want, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerateTypeScript_ExtraDirective(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{
+		Name: "Document",
+		Fields: []parser.StructField{
+			{Name: "ID", Type: "int", Tags: `json:"id"`},
+			{Name: "Metadata", Type: "map[string]interface{}", Tags: `json:"metadata"`, Directives: []string{"extra"}},
+			{Name: "Labels", Type: "map[string]string", Tags: `json:"labels"`},
+			{Name: "Title", Type: "string", Tags: `json:"title"`, Directives: []string{"extra"}},
+		},
+	}}}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want string
+	}{
+		{
+			name: "index signature",
+			want: "  id: number;\n  [key: string]: any;\n  labels: { [key: string]: string };\n  title: string;\n",
+		},
+		{
+			name: "any as unknown",
+			opts: generator.Options{AnyAsUnknown: true},
+			want: "  [key: string]: unknown;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateString(t, data, tt.opts)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected %q in output:\n%s", tt.want, got)
+			}
+		})
+	}
+
+	// the value type of the index signature is widened to the types of the
+	// other properties, inherited ones included, which must be assignable to it
+	data = parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Base", Fields: []parser.StructField{{Name: "Active", Type: "bool", Tags: `json:"active"`}}},
+		{Name: "Labels", Embeds: []string{"Base"}, Fields: []parser.StructField{
+			{Name: "ID", Type: "int", Tags: `json:"id"`},
+			{Name: "Name", Type: "string", Tags: `json:"name"`},
+			{Name: "Rest", Type: "map[string]string", Tags: `json:"rest"`, Directives: []string{"extra"}},
+		}},
+	}}
+	want := "export interface Labels extends Base {\n  id: number;\n  name: string;\n  [key: string]: string | number | boolean;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Labels"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_TypeParamConstraints(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+	want := "export interface GenericPair<A, B extends number> {\n  first: A;\n  second: B;\n}"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in output:\n%s", want, interfaceBlock(t, out, "GenericPair"))
+	}
+
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "Number", Underlying: "interface{ ~int | ~float64 }", Defined: true},
+			{
+				Name:        "Batch",
+				TypeParams:  []string{"S", "E"},
+				Constraints: []string{"interface{ ~[]E }", "comparable"},
+				Underlying:  "map[string]S",
+				Defined:     true,
+			},
+		},
+		Structs: []parser.GoStruct{{
+			Name:        "Stats",
+			TypeParams:  []string{"N", "K", "V"},
+			Constraints: []string{"Number", "interface{ ~string | ~int }", "fmt.Stringer"},
+			Fields:      []parser.StructField{{Name: "Total", Type: "N", Tags: `json:"total"`}},
+		}},
+	}
+	got := generateString(t, data, generator.Options{})
+	for _, want := range []string{
+		"export type Batch<S extends E[], E> = { [key: string]: S };",
+		"export interface Stats<N extends number, K extends string | number, V> {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_MaxAny(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{
+		Name: "Event",
+		Fields: []parser.StructField{
+			{Name: "ID", Type: "int", Tags: `json:"id"`},
+			{Name: "Payload", Type: "interface{}", Tags: `json:"payload"`},
+			{Name: "Extra", Type: "map[string]any", Tags: `json:"extra"`},
+			{Name: "Num", Type: "complex128", Tags: `json:"num"`},
+		},
+	}}}
+	outPath := filepath.Join(t.TempDir(), "types.ts")
+	budget := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		opts    generator.Options
+		wantErr string
+	}{
+		{name: "unlimited by default"},
+		{name: "within budget", opts: generator.Options{MaxAny: budget(3)}},
+		{
+			name:    "over budget",
+			opts:    generator.Options{MaxAny: budget(2)},
+			wantErr: "3 fields are typed any, more than the budget of 2:\n  Event.Payload (interface{})\n  Event.Extra (map[string]any)\n  Event.Num (complex128)",
+		},
+		{
+			name:    "counted with any as unknown",
+			opts:    generator.Options{MaxAny: budget(0), AnyAsUnknown: true},
+			wantErr: "3 fields are typed any",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := generator.GenerateTypeScriptWithOptions(data, outPath, tt.opts)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_Emit(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Status", Underlying: "int", Defined: true}},
+		Enums: []parser.GoEnum{{Name: "Status", BaseType: "int", Members: []parser.EnumMember{
+			{Name: "StatusActive", Value: "0"},
+			{Name: "StatusClosed", Value: "1"},
+		}}},
+		Structs: []parser.GoStruct{
+			{Name: "FooData", Fields: []parser.StructField{{Name: "A", Type: "int"}}},
+			{Name: "BarData", Fields: []parser.StructField{{Name: "B", Type: "string"}}},
+			{Name: "Webhook", Fields: []parser.StructField{
+				{Name: "Type", Type: "string", Tags: `json:"type"`},
+				{Name: "Foo", Type: "*FooData", Tags: `json:"foo,omitempty"`},
+				{Name: "Bar", Type: "*BarData", Tags: `json:"bar,omitempty"`},
+			}},
+		},
+	}
+	base := generator.Options{EnumLabels: true, OneOfUnions: true, TypeGuards: true}
+
+	tests := []struct {
+		name        string
+		emit        generator.Emit
+		typesImport string
+		want        []string
+		notWant     []string
+	}{
+		{
+			name: "all",
+			emit: generator.EmitAll,
+			want: []string{"export type Status = number;", "export const StatusLabels", "export interface FooData", "export function isWebhookFoo"},
+		},
+		{
+			name:    "types",
+			emit:    generator.EmitTypes,
+			want:    []string{"export type Status = number;", "export interface FooData", "export type Webhook ="},
+			notWant: []string{"export const", "export function", "import"},
+		},
+		{
+			name:    "values",
+			emit:    generator.EmitValues,
+			want:    []string{"import type { Status, Webhook } from \"./types\";\n\nexport const StatusLabels", "export function isWebhookFoo"},
+			notWant: []string{"export type", "export interface"},
+		},
+		{
+			name:        "values with types import",
+			emit:        generator.EmitValues,
+			typesImport: "@app/types",
+			want:        []string{"import type { Status, Webhook } from \"@app/types\";"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			opts.Emit = tt.emit
+			opts.TypesImport = tt.typesImport
+			got := generateString(t, data, opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in output:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("unexpected %q in output:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_UnnamedJSONTags(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{
+		Name: "Account",
+		Fields: []parser.StructField{
+			{Name: "Nickname", Type: "string", Tags: `json:",omitempty"`},
+			{Name: "Balance", Type: "int64", Tags: `json:",string"`},
+			{Name: "Limit", Type: "*float64", Tags: `json:",string,omitempty"`},
+			{Name: "Verified", Type: "bool", Tags: `json:"verified,string"`},
+			{Name: "Tags", Type: "[]string", Tags: `json:",string"`},
+			{Name: "Internal", Type: "string", Tags: `json:"-"`},
+		},
+	}}}
+
+	got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Account")
+	want := "export interface Account {\n" +
+		"  Nickname?: string;\n" +
+		"  Balance: string;\n" +
+		"  Limit?: string | null;\n" +
+		"  verified: string;\n" +
+		"  Tags: string[];\n}"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateOpenAPI_Golden(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "Status", Underlying: "string", Defined: true},
+			{Name: "Email", Underlying: "string", Defined: true},
+		},
+		Enums: []parser.GoEnum{{Name: "Status", BaseType: "string", Members: []parser.EnumMember{
+			{Name: "StatusActive", Value: `"active"`},
+			{Name: "StatusClosed", Value: `"closed"`},
+		}}},
+		Structs: []parser.GoStruct{
+			{Name: "Base", Fields: []parser.StructField{
+				{Name: "ID", Type: "int64", Tags: `json:"id,string"`},
+				{Name: "CreatedAt", Type: "time.Time", Tags: `json:"created_at"`},
+			}},
+			{Name: "UserAccount", Embeds: []string{"Base"}, Fields: []parser.StructField{
+				{Name: "Email", Type: "Email", Tags: `json:"email"`},
+				{Name: "Status", Type: "Status", Tags: `json:"status"`},
+				{Name: "Score", Type: "*float64", Tags: `json:"score"`},
+				{Name: "Manager", Type: "*UserAccount", Tags: `json:"manager,omitempty"`},
+				{Name: "Tags", Type: "[]string", Tags: `json:"tags"`},
+				{Name: "Attributes", Type: "map[string]interface{}", Tags: `json:"attributes,omitempty"`},
+				{Name: "Avatar", Type: "[]byte", Tags: `json:"avatar"`},
+				{Name: "Digest", Type: "[32]byte", Tags: `json:"digest"`},
+				{Name: "Position", Type: "[2]float64", Tags: `json:"position"`},
+				{Name: "Nickname", Type: "string", Tags: `json:"nickname"`, Directives: []string{"nullable"}},
+				{Name: "Password", Type: "string", Tags: `json:"-"`},
+			}},
+			{Name: "Page", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "Items", Type: "[]T", Tags: `json:"items"`}}},
+		},
+	}
+
+	outPath := filepath.Join(t.TempDir(), "openapi.json")
+	if err := generator.GenerateTypeScriptWithOptions(data, outPath, generator.Options{OpenAPI: true}); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(got) {
+		t.Fatalf("output is not valid JSON:\n%s", got)
+	}
+
+	path := filepath.Join("..", "..", "test", "testdata", "golden", "openapi.json")
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("output differs from %s:\n%s", path, got)
+	}
+}
+
+func TestGenerateOpenAPI_Diagnostics(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "UserAccount", Fields: []parser.StructField{{Name: "Name", Type: "string", Tags: `json:"name"`}}},
+		{Name: "Page", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "Items", Type: "[]T", Tags: `json:"items"`}}},
+		{Name: "Listing", Fields: []parser.StructField{
+			{Name: "Users", Type: "Page[UserAccount]", Tags: `json:"users"`},
+			{Name: "Meta", Type: "struct{ Total int }", Tags: `json:"meta"`},
+			{Name: "Extra", Type: "interface{}", Tags: `json:"extra"`},
+		}},
+	}}
+	report := &parser.Report{}
+	generateString(t, data, generator.Options{OpenAPI: true, Config: parser.Config{Report: report}})
+
+	var got []string
+	for _, d := range report.Filter(parser.KindLossy) {
+		got = append(got, d.Scope+": "+d.GoType)
+	}
+	want := []string{"Page: Page", "Listing.Users: Page[UserAccount]", "Listing.Meta: struct{ Total int }"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lossy diagnostics = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateTypeScript_TaggedEmbeddedFields(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Audit", Fields: []parser.StructField{{Name: "By", Type: "string", Tags: `json:"by"`}}},
+		{Name: "Paging", Fields: []parser.StructField{{Name: "Page", Type: "int", Tags: `json:"page"`}}},
+		{Name: "Meta", Fields: []parser.StructField{{Name: "Version", Type: "int", Tags: `json:"version"`}}},
+		{
+			Name:   "Order",
+			Embeds: []string{"Meta"},
+			Fields: []parser.StructField{
+				{Name: "Audit", Type: "Audit", Tags: `json:"audit,omitempty"`},
+				{Name: "Paging", Type: "*Paging", Tags: `json:"paging"`},
+				{Name: "ID", Type: "int", Tags: `json:"id"`},
+			},
+		},
+	}}
+
+	got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Order")
+	want := "export interface Order extends Meta {\n" +
+		"  audit?: Audit;\n" +
+		"  paging: Paging | null;\n" +
+		"  id: number;\n}"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_BannerFile(t *testing.T) {
+	dir := t.TempDir()
+	banner := filepath.Join(dir, "banner.txt")
+	content := "/* Copyright 2026 Example Corp. */\n/* eslint-disable */\n// @ts-nocheck"
+	if err := os.WriteFile(banner, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "User", Fields: []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}}}}}
+
+	got := generateString(t, data, generator.Options{BannerFile: banner})
+	if !strings.HasPrefix(got, content+"\n// Generated by go2ts") {
+		t.Errorf("expected the banner before the generated-by header, got:\n%s", got)
+	}
+	if strings.Count(got, "eslint-disable") != 1 {
+		t.Errorf("expected the banner once, got:\n%s", got)
+	}
+
+	err := generator.GenerateTypeScriptWithOptions(data, filepath.Join(dir, "types.ts"),
+		generator.Options{BannerFile: filepath.Join(dir, "missing.txt")})
+	if err == nil || !strings.Contains(err.Error(), "failed to read banner file") {
+		t.Errorf("expected banner read error, got %v", err)
+	}
+}
+
+func TestGenerateTypeScript_EnumReferences(t *testing.T) {
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "OrderStatus", Underlying: "int", Defined: true},
+			{Name: "UserStatus", Underlying: "string", Defined: true},
+			{Name: "Cents", Underlying: "int64", Defined: true},
+		},
+		Enums: []parser.GoEnum{
+			{Name: "OrderStatus", BaseType: "int", Members: []parser.EnumMember{{Name: "OrderPending", Value: "0"}}},
+			{Name: "UserStatus", BaseType: "string", Members: []parser.EnumMember{{Name: "UserActive", Value: `"active"`}}},
+		},
+		Structs: []parser.GoStruct{{
+			Name: "Order",
+			Fields: []parser.StructField{
+				{Name: "Status", Type: "OrderStatus", Tags: `json:"status"`},
+				{Name: "Previous", Type: "*OrderStatus", Tags: `json:"previous"`},
+				{Name: "Owner", Type: "*UserStatus", Tags: `json:"owner,omitempty"`},
+				{Name: "History", Type: "[]*OrderStatus", Tags: `json:"history"`},
+				{Name: "ByUser", Type: "map[string]UserStatus", Tags: `json:"by_user"`},
+				{Name: "Total", Type: "*Cents", Tags: `json:"total"`},
+			},
+		}},
+	}
+
+	got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Order")
+	want := "export interface Order {\n" +
+		"  status: OrderStatus;\n" +
+		"  previous: OrderStatus | null;\n" +
+		"  owner?: UserStatus | null;\n" +
+		"  history: (OrderStatus | null)[];\n" +
+		"  by_user: { [key: string]: UserStatus };\n" +
+		"  total: number | null;\n}"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_EnumValues(t *testing.T) {
+	out := generateModel(t, generator.Options{EnumValues: true})
+	for _, want := range []string{
+		"export const userStatuses = [0, 1, 2] as const satisfies readonly UserStatus[];\n",
+		"export const orderStatuses = [0, 1, 2, 3, 4] as const satisfies readonly OrderStatus[];\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{{Name: "Category", Underlying: "string", Defined: true}},
+		Enums: []parser.GoEnum{{Name: "Category", BaseType: "string", Members: []parser.EnumMember{
+			{Name: "CategoryBook", Value: `"book"`},
+			{Name: "CategoryFilm", Value: `"film"`},
+			{Name: "CategoryMovie", Value: `"film"`},
+		}}},
+	}
+	got := generateString(t, data, generator.Options{EnumValues: true, SingleQuote: true})
+	want := "export const categories = ['book', 'film'] as const satisfies readonly Category[];\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "export const") {
+		t.Errorf("enum values should be off by default:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_SliceOfPointerToGenericOfPointer(t *testing.T) {
+	tests := []struct {
+		name string
+		opts generator.Options
+		want string
+	}{
+		{
+			name: "default",
+			want: "export interface ResultUserList {\n  elements: (GenericResult<UserAccount | null> | null)[];\n}",
+		},
+		{
+			name: "readonly arrays",
+			opts: generator.Options{Config: parser.Config{ReadonlyArrays: true}},
+			want: "export interface ResultUserList {\n  elements: readonly (GenericResult<UserAccount | null> | null)[];\n}",
+		},
+		{
+			name: "optional pointers",
+			opts: generator.Options{Pointers: generator.PointerOptional},
+			want: "export interface ResultUserList {\n  elements: (GenericResult<UserAccount | null> | null)[];\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interfaceBlock(t, generateModel(t, tt.opts), "ResultUserList")
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	structMap := map[string]parser.StructInfo{
+		"GenericResult": {Name: "GenericResult", TypeParams: []string{"T"}},
+		"UserAccount":   {Name: "UserAccount"},
+	}
+	for goType, want := range map[string]string{
+		"*[]*GenericResult[*UserAccount]":           "(GenericResult<UserAccount | null> | null)[] | null",
+		"[][]*GenericResult[*UserAccount]":          "(GenericResult<UserAccount | null> | null)[][]",
+		"map[string][]*GenericResult[*UserAccount]": "{ [key: string]: (GenericResult<UserAccount | null> | null)[] }",
+		"[]*GenericResult[[]*UserAccount]":          "(GenericResult<(UserAccount | null)[]> | null)[]",
+	} {
+		got := parser.GoTypeToTSType(goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", goType, got, want)
+		}
+	}
+}
+
+func TestGenerateTypeScript_DynamicGenericArgs(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Response", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "Data", Type: "T", Tags: `json:"data"`}}},
+		{Name: "Holder", Fields: []parser.StructField{
+			{Name: "Err", Type: "Response[error]", Tags: `json:"err"`},
+			{Name: "Iface", Type: "Response[interface{}]", Tags: `json:"iface"`},
+			{Name: "Any", Type: "Response[any]", Tags: `json:"any"`},
+			{Name: "Nested", Type: "Response[map[string][]error]", Tags: `json:"nested"`},
+		}},
+	}}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want string
+	}{
+		{
+			name: "default",
+			want: "  err: Response<Error>;\n  iface: Response<any>;\n  any: Response<any>;\n  nested: Response<{ [key: string]: Error[] }>;\n",
+		},
+		{
+			name: "any as unknown",
+			opts: generator.Options{AnyAsUnknown: true},
+			want: "  err: Response<Error>;\n  iface: Response<unknown>;\n  any: Response<unknown>;\n  nested: Response<{ [key: string]: Error[] }>;\n",
+		},
+		{
+			name: "error as string",
+			opts: generator.Options{Config: parser.Config{ErrorAsString: true}},
+			want: "  err: Response<string>;\n  iface: Response<any>;\n  any: Response<any>;\n  nested: Response<{ [key: string]: string[] }>;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interfaceBlock(t, generateString(t, data, tt.opts), "Holder")
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected %q in:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_ForceModule(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "User", Fields: []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}}}}}
+
+	tests := []struct {
+		name string
+		data parser.GoFileData
+		opts generator.Options
+		want string
+	}{
+		{"force module", data, generator.Options{ForceModule: true}, "export interface User {\n  id: number;\n}\n\nexport {};\n"},
+		{"declare global", data, generator.Options{DeclareGlobal: true}, "  }\n}\n\nexport {};\n"},
+		{"declare global and force module", data, generator.Options{DeclareGlobal: true, ForceModule: true}, "  }\n}\n\nexport {};\n"},
+		{"no declarations", parser.GoFileData{}, generator.Options{ForceModule: true}, "\n\nexport {};\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateString(t, tt.data, tt.opts)
+			if !strings.HasSuffix(got, tt.want) || strings.Count(got, "export {};") != 1 {
+				t.Errorf("expected output ending with %q, got:\n%s", tt.want, got)
+			}
+		})
+	}
+
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "export {};") {
+		t.Errorf("unexpected module trailer by default:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_NamedCollectionTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type User struct{ ID int }
+
+type IntList []int
+
+type StringSet map[string]struct{}
+
+type ScoreByName map[string]float64
+
+type UserRef *User
+
+type Users []*User
+
+type Matrix [3][3]float64
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	got := generateString(t, data, generator.Options{})
+	for _, want := range []string{
+		"export type IntList = number[];\n",
+		"export type StringSet = { [key: string]: any };\n",
+		"export type ScoreByName = { [key: string]: number };\n",
+		"export type UserRef = User | null;\n",
+		"export type Users = (User | null)[];\n",
+		"export type Matrix = number[][];\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	got = generateString(t, data, generator.Options{Config: parser.Config{Sets: parser.SetAsSet}})
+	if want := "export type StringSet = Set<string>;\n"; !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+}
+
+func TestGenerateTypeScript_Comments(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "Person", Fields: []parser.StructField{
+		{Name: "Name", Type: "string", Tags: `json:"name"`, Doc: "Name is the full name.\nIt may contain spaces."},
+		{Name: "Age", Type: "int", Tags: `json:"age" example:"42"`, Comment: "years"},
+		{Name: "Nickname", Type: "string", Tags: `json:"nickname"`, Doc: "Nickname is optional.", Comment: "ends */ early"},
+		{Name: "ID", Type: "int", Tags: `json:"id"`},
+	}}}}
+
+	want := "export interface Person {\n" +
+		"  /**\n" +
+		"   * Name is the full name.\n" +
+		"   * It may contain spaces.\n" +
+		"   */\n" +
+		"  name: string;\n" +
+		"  /**\n" +
+		"   * years\n" +
+		"   * @example 42\n" +
+		"   */\n" +
+		"  age: number;\n" +
+		"  /**\n" +
+		"   * Nickname is optional.\n" +
+		"   * ends *\\/ early\n" +
+		"   */\n" +
+		"  nickname: string;\n" +
+		"  id: number;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{Comments: true, Examples: true}), "Person"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	want = "  /** years */\n  age: number;\n"
+	if got := generateString(t, data, generator.Options{Comments: true}); !strings.Contains(got, want) {
+		t.Errorf("expected a one-line JSDoc for the line comment:\n%s", got)
+	}
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "years") {
+		t.Errorf("comments should be opt-in:\n%s", got)
+	}
+}
+
+func TestMergeDeclarations(t *testing.T) {
+	marker := generator.DeclarationsMarker
+	gen := "// Generated by go2ts\n\n" +
+		"export type Status = \"active\"\n  | \"closed\";\n\n" +
+		"/** A user. */\nexport interface User {\n  /**\n   * name { of } the user\n   */\n  name: string;\n}\n\n" +
+		"export interface Order {\n  id: number;\n}\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			"EmptyFile",
+			"",
+			gen + "\n" + marker + " Status User Order\n",
+		},
+		{
+			"ReplaceAndKeepManual",
+			"// Generated by go2ts\n\nimport { z } from \"zod\";\n\n" +
+				"export interface User {\n  name: string;\n  age: number;\n}\n\n" +
+				"// hand-written\nexport const userSchema = z.object({\n  name: z.string(),\n});\n\n" +
+				"export interface Order {\n  total: number;\n}\n",
+			"// Generated by go2ts\n\nimport { z } from \"zod\";\n\n" +
+				"/** A user. */\nexport interface User {\n  /**\n   * name { of } the user\n   */\n  name: string;\n}\n\n" +
+				"// hand-written\nexport const userSchema = z.object({\n  name: z.string(),\n});\n\n" +
+				"export interface Order {\n  id: number;\n}\n\n" +
+				"export type Status = \"active\"\n  | \"closed\";\n\n" +
+				marker + " Status User Order\n",
+		},
+		{
+			"AppendInOrder",
+			"export type Manual = string;\n",
+			"export type Manual = string;\n\n" + strings.TrimPrefix(gen, "// Generated by go2ts\n\n") + "\n" + marker + " Status User Order\n",
+		},
+		{
+			"InsertAfterPreviousAndRemoveStale",
+			"export type Status = \"active\";\n\n" +
+				"export interface Removed {\n  id: number;\n}\n\n" +
+				"export type Manual = string;\n\n" +
+				"export interface Order {\n  id: number;\n}\n\n" +
+				marker + " Status Removed Order\n",
+			"export type Status = \"active\"\n  | \"closed\";\n\n" +
+				"/** A user. */\nexport interface User {\n  /**\n   * name { of } the user\n   */\n  name: string;\n}\n\n" +
+				"export type Manual = string;\n\n" +
+				"export interface Order {\n  id: number;\n}\n\n" +
+				marker + " Status User Order\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generator.MergeDeclarations(tt.existing, gen)
+			if got != tt.want {
+				t.Errorf("MergeDeclarations() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			if again := generator.MergeDeclarations(got, gen); again != got {
+				t.Errorf("merging again changed the file:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScript_GoManifest(t *testing.T) {
+	data := parser.GoFileData{
+		Structs: []parser.GoStruct{
+			{Name: "User", Package: "model", Fields: []parser.StructField{{Name: "ID", Type: "UserID"}}},
+			{Name: "Order", Package: "model", Fields: []parser.StructField{{Name: "Status", Type: "OrderStatus"}}},
+		},
+		Aliases: []parser.TypeAlias{
+			{Name: "UserID", Underlying: "string", Package: "model"},
+			{Name: "OrderStatus", Underlying: "int", Defined: true, Package: "model"},
+		},
+		Enums: []parser.GoEnum{{Name: "OrderStatus", BaseType: "int", Members: []parser.EnumMember{{Name: "Pending", Value: "0"}}}},
+	}
+
+	path := filepath.Join(t.TempDir(), "go2ts_types.go")
+	generateString(t, data, generator.Options{GoManifest: path, TypePrefix: "Api"})
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read Go manifest: %v", err)
+	}
+	want := "// Code generated by go2ts. DO NOT EDIT.\n\n" +
+		"package model\n\n" +
+		"// TypeScriptTypes maps the name of each Go type converted by go2ts to\n" +
+		"// the name of its TypeScript declaration.\n" +
+		"var TypeScriptTypes = map[string]string{\n" +
+		"\t\"Order\":       \"ApiOrder\",\n" +
+		"\t\"OrderStatus\": \"ApiOrderStatus\",\n" +
+		"\t\"User\":        \"ApiUser\",\n" +
+		"\t\"UserID\":      \"ApiUserID\",\n" +
+		"}\n"
+	if string(out) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+
+	generateString(t, data, generator.Options{GoManifest: path, GoManifestPackage: "model_test"})
+	if out, err := os.ReadFile(path); err != nil || !strings.Contains(string(out), "package model_test\n") {
+		t.Errorf("expected the configured package, got %s, %v", out, err)
+	}
+
+	outPath := filepath.Join(t.TempDir(), "types.ts")
+	err = generator.GenerateTypeScriptWithOptions(data, outPath, generator.Options{GoManifest: path, GoManifestPackage: "not-a-package"})
+	if err == nil || !strings.Contains(err.Error(), "invalid Go manifest package name") {
+		t.Errorf("expected an invalid package error, got %v", err)
+	}
+
+	// types merged from several packages need an explicit package
+	data.Structs[1].Package, data.Structs[1].ImportPath = "model", "example.com/billing/model"
+	err = generator.GenerateTypeScriptWithOptions(data, outPath, generator.Options{GoManifest: path})
+	if err == nil || !strings.Contains(err.Error(), "set the Go manifest package") {
+		t.Errorf("expected a missing package error, got %v", err)
+	}
+	generateString(t, data, generator.Options{GoManifest: path, GoManifestPackage: "api"})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o644 {
+		t.Errorf("mode = %v, want 0644", got)
+	}
+}
+
+func TestGenerateTypeScript_OmitEmptyOptional(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "Person", Fields: []parser.StructField{
+		{Name: "Age", Type: "*int", Tags: `json:"age,omitempty"`},
+		{Name: "Height", Type: "*int", Tags: `json:"height"`},
+		{Name: "Name", Type: "string", Tags: `json:"name,omitempty"`},
+		{Name: "Nick", Type: "string", Tags: `json:"nick"`},
+		{Name: "Secret", Type: "string", Tags: `json:"-"`},
+		{Name: "Dash", Type: "int", Tags: `json:"-,omitempty"`},
+	}}}}
+
+	want := "export interface Person {\n" +
+		"  age?: number | null;\n" +
+		"  height: number | null;\n" +
+		"  name?: string;\n" +
+		"  nick: string;\n" +
+		"  \"-\"?: number;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Person"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_TSEnums(t *testing.T) {
+	out := generateModel(t, generator.Options{TSEnums: true, EnumLabels: true, EnumValues: true})
+
+	for _, want := range []string{
+		"export enum UserStatus {\n  Active = 0,\n  Inactive = 1,\n  Suspended = 2,\n}\n",
+		"export enum PaymentMethod {\n  CreditCard = 0,\n  PayPal = 1,\n  Toss = 2,\n  Other = 3,\n}\n",
+		"  [UserStatus.Active]: \"Active\",\n",
+		"export const paymentMethods = [PaymentMethod.CreditCard, PaymentMethod.PayPal, PaymentMethod.Toss, PaymentMethod.Other] as const satisfies readonly PaymentMethod[];\n",
+		"  status: UserStatus;\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "export type UserStatus") {
+		t.Errorf("enum also declared as a type alias:\n%s", out)
+	}
+
+	data := parser.GoFileData{
+		Aliases: []parser.TypeAlias{
+			{Name: "Color", Underlying: "string", Defined: true},
+			{Name: "Level", Underlying: "int", Defined: true},
+			{Name: "Flag", Underlying: "bool", Defined: true},
+		},
+		Enums: []parser.GoEnum{
+			{Name: "Color", BaseType: "string", Members: []parser.EnumMember{{Name: "ColorRed", Value: `"red"`}}},
+			{Name: "Level", BaseType: "int", Members: []parser.EnumMember{{Name: "Level1", Value: "1"}, {Name: "Level2", Value: "2"}}},
+			{Name: "Flag", BaseType: "bool", Members: []parser.EnumMember{{Name: "FlagOn", Value: "true"}}},
+		},
+	}
+	got := generateString(t, data, generator.Options{TSEnums: true, EnumValues: true, Emit: generator.EmitValues})
+	for _, want := range []string{
+		"import { Color, type Flag, Level } from \"./types\";\n",
+		"export const colors = [Color.Red] as const satisfies readonly Color[];\n",
+		"export const flags = [true] as const satisfies readonly Flag[];\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in values output:\n%s", want, got)
+		}
+	}
+	got = generateString(t, data, generator.Options{TSEnums: true, Emit: generator.EmitTypes})
+	for _, want := range []string{
+		"export enum Color {\n  Red = \"red\",\n}\n",
+		"export enum Level {\n  Level1 = 1,\n  Level2 = 2,\n}\n",
+		"export type Flag = boolean;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in types output:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTypeScript_StringEnumUnions(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+	ColorBlue  Color = "blue"
+	ColorCrimson     = ColorRed
+)
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+type Palette struct {
+	Primary Color   ` + "`json:\"primary\"`" + `
+	Accents []Color ` + "`json:\"accents\"`" + `
+	Level   Level   ` + "`json:\"level\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "enums.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts generator.Options
+		want []string
+	}{
+		{"Unions", generator.Options{StringEnumUnions: true}, []string{
+			"export type Color = \"red\" | \"green\" | \"blue\";\n",
+			"export type Level = number;\n",
+		}},
+		{"WithTSEnums", generator.Options{StringEnumUnions: true, TSEnums: true}, []string{
+			"export type Color = \"red\" | \"green\" | \"blue\";\n",
+			"export enum Level {\n  Low = 0,\n  High = 1,\n}\n",
+		}},
+		{"Default", generator.Options{}, []string{
+			"export type Color = string;\n",
+			"export type Level = number;\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := generateString(t, data, tt.opts)
+			want := append(tt.want, "  primary: Color;\n  accents: Color[];\n  level: Level;\n")
+			for _, w := range want {
+				if !strings.Contains(out, w) {
+					t.Errorf("expected %q in output:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScriptTo(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "User", Fields: []parser.StructField{{Name: "Name", Type: "string", Tags: `json:"name"`}}}}}
+	var buf bytes.Buffer
+	if err := generator.GenerateTypeScriptTo(data, &buf); err != nil {
+		t.Fatalf("GenerateTypeScriptTo failed: %v", err)
+	}
+	if got, want := interfaceBlock(t, buf.String(), "User"), "export interface User {\n  name: string;\n}"; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	var strictBuf bytes.Buffer
+	strictData := parser.GoFileData{Structs: []parser.GoStruct{{Name: "A", Fields: []parser.StructField{{Name: "B", Type: "Missing"}}}}}
+	err := generator.GenerateTypeScriptToWithOptions(strictData, &strictBuf, generator.Options{Strict: true})
+	if err == nil || strictBuf.Len() != 0 {
+		t.Errorf("expected a strict error and nothing written, got %v and %q", err, strictBuf.String())
+	}
+}
+
+func TestGenerateTypeScript_TypeComments(t *testing.T) {
+	out := generateModel(t, generator.Options{Comments: true})
+	for _, want := range []string{
+		"/** 1. Basic personal info */\nexport interface BasicPersonInfo {\n",
+		"/** 4. Type alias for custom int */\nexport type CustomInt = number;\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	data := parser.GoFileData{Structs: []parser.GoStruct{{
+		Name:   "Person",
+		Doc:    "Person is a person.\n\nIt has a name, */ escaped.",
+		Fields: []parser.StructField{{Name: "Name", Type: "string", Tags: `json:"name"`, Doc: "Name is the full name."}},
+	}}}
+	want := "/**\n" +
+		" * Person is a person.\n" +
+		" *\n" +
+		" * It has a name, *\\/ escaped.\n" +
+		" */\n" +
+		"export interface Person {\n" +
+		"  /** Name is the full name. */\n" +
+		"  name: string;\n" +
+		"}\n"
+	if got := generateString(t, data, generator.Options{Comments: true}); !strings.Contains(got, want) {
+		t.Errorf("expected %q in output:\n%s", want, got)
+	}
+	if got := generateString(t, data, generator.Options{}); strings.Contains(got, "/**") {
+		t.Errorf("comments should be opt-in:\n%s", got)
+	}
+}
+
+func TestGenerateTypeScript_EmbedStrategy(t *testing.T) {
+	tests := []struct {
+		strategy generator.EmbedStrategy
+		want     map[string]string
+	}{
+		{generator.EmbedExtendsOmit, map[string]string{
+			"AdminAccount":           "export interface AdminAccount extends UserAccount {\n  admin_level: number;\n}",
+			"LoggedService":          "export interface LoggedService extends Logger {\n  name: string;\n}",
+			"StructBWithConflict":    "export interface StructBWithConflict extends Omit<StructAWithField, \"field\"> {\n  field: string;\n}",
+			"AnonymousEmbeddedBasic": "export interface AnonymousEmbeddedBasic extends Partial<BasicPersonInfo> {\n  score: number;\n}",
+		}},
+		{generator.EmbedExtends, map[string]string{
+			"AdminAccount":        "export interface AdminAccount extends UserAccount {\n  admin_level: number;\n}",
+			"LoggedService":       "export interface LoggedService extends Logger {\n  name: string;\n}",
+			"StructBWithConflict": "export interface StructBWithConflict {\n  field: string;\n}",
+		}},
+		{generator.EmbedFlatten, map[string]string{
+			"LoggedService":          "export interface LoggedService {\n  name: string;\n}",
+			"StructBWithConflict":    "export interface StructBWithConflict {\n  field: string;\n}",
+			"EmbeddedBasicInfo":      "export interface EmbeddedBasicInfo {\n  id: number;\n  name?: string;\n  age?: number | null;\n  extra_field: string;\n}",
+			"AnonymousEmbeddedBasic": "export interface AnonymousEmbeddedBasic {\n  id?: number;\n  name?: string;\n  age?: number | null;\n  score: number;\n}",
+			"EmbeddedOnly":           "export interface EmbeddedOnly {\n  EmbField?: string;\n}",
+		}},
+	}
+	for _, tt := range tests {
+		out := generateModel(t, generator.Options{EmbedStrategy: tt.strategy})
+		for name, want := range tt.want {
+			if got := interfaceBlock(t, out, name); got != want {
+				t.Errorf("strategy %d: got:\n%s\nwant:\n%s", tt.strategy, got, want)
+			}
+		}
+	}
+
+	flat := interfaceBlock(t, generateModel(t, generator.Options{EmbedStrategy: generator.EmbedFlatten}), "AdminAccount")
+	if !strings.HasPrefix(flat, "export interface AdminAccount {\n  id: number;\n") || !strings.HasSuffix(flat, "  admin_level: number;\n}") {
+		t.Errorf("expected the fields of UserAccount flattened into AdminAccount, got:\n%s", flat)
+	}
+
+	// promoted fields of generic embeds are instantiated; a shallower field
+	// shadows a deeper one and fields at the same depth are ambiguous
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Base", Fields: []parser.StructField{
+			{Name: "ID", Type: "int", Tags: `json:"id"`},
+			{Name: "Note", Type: "string", Tags: `json:"note"`},
+		}},
+		{Name: "Page", TypeParams: []string{"T"}, Embeds: []string{"Base"}, Fields: []parser.StructField{
+			{Name: "Items", Type: "[]T", Tags: `json:"items"`},
+			{Name: "Note", Type: "bool", Tags: `json:"note"`},
+		}},
+		{Name: "Other", Fields: []parser.StructField{{Name: "Items", Type: "int", Tags: `json:"items"`}}},
+		{Name: "Users", Embeds: []string{"*Page[User]"}, Fields: []parser.StructField{{Name: "Total", Type: "int", Tags: `json:"total"`}}},
+		{Name: "Mixed", Embeds: []string{"Page[string]", "Other"}},
+		{Name: "User", Fields: []parser.StructField{{Name: "Name", Type: "string", Tags: `json:"name"`}}},
+	}}
+	out := generateString(t, data, generator.Options{EmbedStrategy: generator.EmbedFlatten})
+	want := map[string]string{
+		"Users": "export interface Users {\n  items?: User[];\n  note?: boolean;\n  id?: number;\n  total: number;\n}",
+		"Mixed": "export interface Mixed {\n  note: boolean;\n  id: number;\n}",
+	}
+	for name, w := range want {
+		if got := interfaceBlock(t, out, name); got != w {
+			t.Errorf("got:\n%s\nwant:\n%s", got, w)
+		}
+	}
+}
+
+func TestGenerateTypeScript_PointerEmbeds(t *testing.T) {
+	// shadowed properties are omitted before the rest is made optional
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Base", Fields: []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}, {Name: "Note", Type: "string", Tags: `json:"note"`}}},
+		{Name: "Doc", Embeds: []string{"*Base"}, Fields: []parser.StructField{{Name: "Note", Type: "int", Tags: `json:"note"`}}},
+	}}
+	want := "export interface Doc extends Partial<Omit<Base, \"note\">> {\n  note: number;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Doc"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript_InlineFields(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+	want := "export interface InlineBasicExample {\n  id: number;\n  name?: string;\n  age?: number | null;\n  notes?: string;\n}"
+	if got := interfaceBlock(t, out, "InlineBasicExample"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// inlined pointers are optional, generic type arguments are applied,
+	// and a named ",inline" tag is an ordinary property
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Meta", TypeParams: []string{"T"}, Fields: []parser.StructField{{Name: "Value", Type: "T", Tags: `json:"value"`}}},
+		{Name: "Audit", Fields: []parser.StructField{{Name: "At", Type: "string", Tags: `json:"at"`}}},
+		{Name: "Doc", Fields: []parser.StructField{
+			{Name: "Meta", Type: "Meta[int]", Tags: `json:",inline"`},
+			{Name: "Audit", Type: "*Audit", Tags: `json:",inline"`},
+			{Name: "Named", Type: "Audit", Tags: `json:"named,inline"`},
+		}},
+	}}
+	want = "export interface Doc {\n  value: number;\n  at?: string;\n  named: Audit;\n}"
+	if got := interfaceBlock(t, generateString(t, data, generator.Options{}), "Doc"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// every output declares the same properties
+	data = parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "Audit", Fields: []parser.StructField{{Name: "At", Type: "string", Tags: `json:"at"`}}},
+		{Name: "Foo", Fields: []parser.StructField{{Name: "N", Type: "int", Tags: `json:"n"`}}},
+		{Name: "Event", Fields: []parser.StructField{
+			{Name: "Type", Type: "string", Tags: `json:"type"`},
+			{Name: "Audit", Type: "*Audit", Tags: `json:",inline"`},
+			{Name: "Foo", Type: "*Foo", Tags: `json:"foo,omitempty"`},
+			{Name: "Bar", Type: "*Foo", Tags: `json:"bar,omitempty"`},
+		}},
+		{Name: "Tracked", Embeds: []string{"Event"}},
+	}}
+	out = generateString(t, data, generator.Options{OneOfUnions: true})
+	if want := "  | { type: \"foo\"; foo: Foo; at?: string }\n"; !strings.Contains(out, want) {
+		t.Errorf("expected %q in the union, got:\n%s", want, out)
+	}
+	flat := generateString(t, data, generator.Options{EmbedStrategy: generator.EmbedFlatten})
+	if got := interfaceBlock(t, flat, "Tracked"); !strings.Contains(got, "  at?: string;\n") || strings.Contains(got, "Audit") {
+		t.Errorf("expected the inlined property among the promoted ones, got:\n%s", got)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+				Required   []string       `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(generateString(t, data, generator.Options{OpenAPI: true})), &doc); err != nil {
+		t.Fatalf("invalid OpenAPI output: %v", err)
+	}
+	event := doc.Components.Schemas["Event"]
+	if _, ok := event.Properties["at"]; !ok || event.Properties["Audit"] != nil {
+		t.Errorf("expected the inlined property in the schema, got %v", event.Properties)
+	}
+	if !reflect.DeepEqual(event.Required, []string{"type"}) {
+		t.Errorf("expected the inlined pointer property to be optional, got required %v", event.Required)
+	}
+}
+
+func TestGenerateTypeScript_CustomMarshaler(t *testing.T) {
+	out := generateModel(t, generator.Options{})
+	want := "// custom MarshalJSON: shape may differ\nexport interface UserWithCustomMarshal {"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected a warning above UserWithCustomMarshal, got:\n%s", out)
+	}
+	if strings.Count(out, "custom MarshalJSON") != 1 {
+		t.Errorf("expected a single warning, got:\n%s", out)
+	}
+}
+
+func TestGenerateTypeScript_PreserveNamedAliases(t *testing.T) {
+	out := generateModel(t, generator.Options{Config: parser.Config{PreserveNamedAliases: true}})
+	for _, want := range []string{
+		"export type Email = string;\n",
+		"export type CustomInt = number;\n",
+		"  email: Email;\n",
+		"  custom_value: CustomInt;\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// references follow the renamed declarations
+	out = generateModel(t, generator.Options{Config: parser.Config{PreserveNamedAliases: true}, TypePrefix: "Api"})
+	if !strings.Contains(out, "export type ApiEmail = string;\n") || !strings.Contains(out, "  email: ApiEmail;\n") {
+		t.Errorf("expected prefixed alias references, got:\n%s", out)
+	}
+}
+
+func TestGenerateTypeScript_MapsOfPointerSlices(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{
+		{Name: "BasicPersonInfo", Fields: []parser.StructField{{Name: "ID", Type: "int", Tags: `json:"id"`}}},
+		{Name: "Index", Fields: []parser.StructField{
+			{Name: "Counts", Type: "map[string][]*int", Tags: `json:"counts"`},
+			{Name: "People", Type: "map[int][]*BasicPersonInfo", Tags: `json:"people"`},
+		}},
+	}}
+
+	tests := []struct {
+		opts generator.Options
+		want string
+	}{
+		{generator.Options{}, "export interface Index {\n" +
+			"  counts: { [key: string]: (number | null)[] };\n" +
+			"  people: { [key: number]: (BasicPersonInfo | null)[] };\n}"},
+		{generator.Options{Config: parser.Config{ReadonlyArrays: true}}, "export interface Index {\n" +
+			"  counts: { [key: string]: readonly (number | null)[] };\n" +
+			"  people: { [key: number]: readonly (BasicPersonInfo | null)[] };\n}"},
+	}
+	for _, tt := range tests {
+		if got := interfaceBlock(t, generateString(t, data, tt.opts), "Index"); got != tt.want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTypeScript_ReplacesOutputInPlace(t *testing.T) {
+	data := parser.GoFileData{Structs: []parser.GoStruct{{Name: "User", Fields: []parser.StructField{{Name: "Name", Type: "string"}}}}}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "types.ts")
+	if err := os.WriteFile(target, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.ts")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := generator.GenerateTypeScriptWithOptions(data, link, generator.Options{}); err != nil {
+		t.Fatalf("GenerateTypeScriptWithOptions failed: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("the symlink was replaced: %v, %v", info, err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0o600 {
+		t.Errorf("mode = %v, want the existing 0600", got)
+	}
+	out, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "export interface User") {
+		t.Errorf("the link target was not written:\n%s", out)
+	}
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+// GoManifestVar is the variable of the Go manifest file, mapping the name of
+// each converted Go type to the name of its TypeScript declaration.
+const GoManifestVar = "TypeScriptTypes"
+
+// renderGoManifest returns the Go manifest file of data: a generated Go
+// source file of package pkg declaring
+//
+//	var TypeScriptTypes = map[string]string{
+//		"UserAccount": "ApiUserAccount",
+//	}
+//
+// with an entry per struct, alias and enum, sorted by Go name. A lint or a
+// test can compare it with the types declared in Go to find those added
+// since the TypeScript output was last generated.
+func renderGoManifest(data parser.GoFileData, pkg string, opts *Options) (string, error) {
+	if pkg == "" {
+		var err error
+		if pkg, err = manifestPackage(data); err != nil {
+			return "", err
+		}
+	}
+	if !token.IsIdentifier(pkg) {
+		return "", fmt.Errorf("invalid Go manifest package name %q", pkg)
+	}
+
+	var names []string
+	for _, s := range data.Structs {
+		names = append(names, s.Name)
+	}
+	for _, a := range data.Aliases {
+		names = append(names, a.Name)
+	}
+	for _, e := range data.Enums {
+		names = append(names, e.Name)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var b strings.Builder
+	b.WriteString("// Code generated by go2ts. DO NOT EDIT.\n\n")
+	b.WriteString("package " + pkg + "\n\n")
+	b.WriteString("// " + GoManifestVar + " maps the name of each Go type converted by go2ts to\n")
+	b.WriteString("// the name of its TypeScript declaration.\n")
+	b.WriteString("var " + GoManifestVar + " = map[string]string{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%s: %s,\n", strconv.Quote(name), strconv.Quote(opts.typeName(name)))
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(src), nil
+}
+
+// manifestPackage returns the package of the types of data, the package
+// the manifest usually sits next to. Types merged from several packages
+// leave the choice to GoManifestPackage.
+func manifestPackage(data parser.GoFileData) (string, error) {
+	packages := map[string]string{} // import path, or name when unknown, to name
+	for _, s := range data.Structs {
+		if s.Package != "" {
+			packages[cmp.Or(s.ImportPath, s.Package)] = s.Package
+		}
+	}
+	for _, a := range data.Aliases {
+		if a.Package != "" {
+			packages[cmp.Or(a.ImportPath, a.Package)] = a.Package
+		}
+	}
+	switch len(packages) {
+	case 0:
+		return "", errors.New("the package of the Go manifest is unknown: set the Go manifest package")
+	case 1:
+		for _, name := range packages {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("the converted types come from %d packages: set the Go manifest package", len(packages))
+}
+
+func writeGoManifest(path string, data parser.GoFileData, opts *Options) error {
+	src, err := renderGoManifest(data, opts.GoManifestPackage, opts)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filepath.Clean(path), src)
+}
@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// typeMapping is one row of the mapping report: a Go type, the TypeScript
+// type it was converted to and the declarations it was encountered in.
+type typeMapping struct {
+	GoType string
+	TSType string
+	UsedBy []string
+}
+
+// mappingTable collects the conversions made while generating.
+type mappingTable struct {
+	rows map[[2]string]*typeMapping
+}
+
+func (m *mappingTable) add(scope, goType, tsType string) {
+	if m.rows == nil {
+		m.rows = map[[2]string]*typeMapping{}
+	}
+	key := [2]string{goType, tsType}
+	row, ok := m.rows[key]
+	if !ok {
+		row = &typeMapping{GoType: goType, TSType: tsType}
+		m.rows[key] = row
+	}
+	row.UsedBy = append(row.UsedBy, scope)
+}
+
+// sorted returns the rows ordered by Go type, then TypeScript type.
+func (m *mappingTable) sorted() []*typeMapping {
+	rows := make([]*typeMapping, 0, len(m.rows))
+	for _, row := range m.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].GoType != rows[j].GoType {
+			return rows[i].GoType < rows[j].GoType
+		}
+		return rows[i].TSType < rows[j].TSType
+	})
+	return rows
+}
+
+// markdown renders the table as a GitHub flavored markdown table.
+func (m *mappingTable) markdown() string {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	var sb strings.Builder
+	sb.WriteString("| Go type | TypeScript type | Used by |\n")
+	sb.WriteString("| --- | --- | --- |\n")
+	for _, row := range m.sorted() {
+		sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s |\n",
+			cell.Replace(row.GoType), cell.Replace(row.TSType), cell.Replace(strings.Join(row.UsedBy, ", "))))
+	}
+	return sb.String()
+}
+
+// csv renders the table as CSV with a header row.
+func (m *mappingTable) csv() (string, error) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	if err := w.Write([]string{"go_type", "ts_type", "used_by"}); err != nil {
+		return "", err
+	}
+	for _, row := range m.sorted() {
+		if err := w.Write([]string{row.GoType, row.TSType, strings.Join(row.UsedBy, ";")}); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+// writeMappingReport writes the table to path, as CSV when the path ends in
+// ".csv" and as a markdown table otherwise.
+func writeMappingReport(path string, m *mappingTable) error {
+	content := m.markdown()
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		var err error
+		if content, err = m.csv(); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Clean(path), []byte(content), 0o600)
+}
@@ -0,0 +1,274 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Markers delimiting the generated region of a merged output file.
+const (
+	BeginMarker = "// go2ts:begin"
+	EndMarker   = "// go2ts:end"
+)
+
+// MergeGenerated - splices generated into the marked region of existing.
+// Content outside the markers is kept as-is. If the markers are missing,
+// a new marked region is appended to the end of existing. A begin marker
+// without an end marker is an error, rather than a second region.
+func MergeGenerated(existing, generated string) (string, error) {
+	if !strings.HasSuffix(generated, "\n") {
+		generated += "\n"
+	}
+	block := BeginMarker + "\n" + generated + EndMarker
+
+	begin := strings.Index(existing, BeginMarker)
+	if begin < 0 {
+		if existing != "" && !strings.HasSuffix(existing, "\n") {
+			existing += "\n"
+		}
+		if existing != "" {
+			existing += "\n"
+		}
+		return existing + block + "\n", nil
+	}
+
+	idx := strings.Index(existing[begin:], EndMarker)
+	if idx < 0 {
+		line := strings.Count(existing[:begin], "\n") + 1
+		return "", fmt.Errorf("unterminated generated region: %q on line %d has no %q", BeginMarker, line, EndMarker)
+	}
+	end := begin + idx + len(EndMarker)
+	return existing[:begin] + block + existing[end:], nil
+}
+
+// DeclarationsMarker starts the comment listing the declarations written by
+// MergeDeclarations, so that the next merge can tell the declarations of
+// removed Go types from hand-written ones.
+const DeclarationsMarker = "// go2ts:declarations"
+
+// declStart matches the first line of a top-level TypeScript declaration
+// and captures its name.
+var declStart = regexp.MustCompile(`^(?:export\s+)?(?:declare\s+)?(?:(?:const\s+enum|abstract\s+class|interface|type|const|let|var|enum|function|class|namespace)\s+([A-Za-z_$][\w$]*)|(global)\b)`)
+
+// segment is a run of lines of a TypeScript file: a top-level declaration
+// with its leading comments, or other text when name is "".
+type segment struct {
+	name string
+	text string
+}
+
+// MergeDeclarations - updates the top-level declarations of existing with
+// those of generated, matched by name, and leaves everything else as-is.
+// A declaration of generated replaces the one of existing with its name, or
+// is inserted after the preceding generated declaration. A declaration
+// written by a previous merge and no longer generated is removed; these are
+// listed on a DeclarationsMarker line ending the file.
+func MergeDeclarations(existing, generated string) string {
+	var names []string
+	decls := map[string]string{}
+	for _, s := range splitDeclarations(generated) {
+		if s.name == "" {
+			continue
+		}
+		if text, ok := decls[s.name]; ok {
+			decls[s.name] = text + "\n" + s.text
+			continue
+		}
+		names = append(names, s.name)
+		decls[s.name] = s.text
+	}
+	manifest := DeclarationsMarker + " " + strings.Join(names, " ") + "\n"
+
+	existing, previous := cutDeclarationsMarker(existing)
+	if strings.TrimSpace(existing) == "" {
+		return strings.TrimRight(generated, "\n") + "\n\n" + manifest
+	}
+
+	segs := splitDeclarations(existing)
+	out := make([]segment, 0, len(segs)+len(names))
+	placed := map[string]bool{}
+	for i := 0; i < len(segs); i++ {
+		s := segs[i]
+		switch _, ok := decls[s.name]; {
+		case s.name == "":
+			out = append(out, s)
+		case ok:
+			if !placed[s.name] {
+				out = append(out, segment{s.name, decls[s.name]})
+				placed[s.name] = true
+			}
+		case previous[s.name]:
+			// removed, with the blank lines following it
+			if i+1 < len(segs) && segs[i+1].name == "" && strings.TrimSpace(segs[i+1].text) == "" {
+				i++
+			}
+		default:
+			out = append(out, s)
+		}
+	}
+
+	for i, name := range names {
+		if placed[name] {
+			continue
+		}
+		at := len(out)
+		for j := i - 1; j >= 0; j-- {
+			if k := slices.IndexFunc(out, func(s segment) bool { return s.name == names[j] }); k >= 0 {
+				at = k + 1
+				break
+			}
+		}
+		out = slices.Insert(out, at, segment{name, "\n" + decls[name]})
+		placed[name] = true
+	}
+
+	var b strings.Builder
+	for _, s := range out {
+		b.WriteString(s.text)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n\n" + manifest
+}
+
+// cutDeclarationsMarker removes the DeclarationsMarker lines of src and
+// returns the names they list.
+func cutDeclarationsMarker(src string) (string, map[string]bool) {
+	names := map[string]bool{}
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(src, "\n") {
+		if list, ok := strings.CutPrefix(strings.TrimSpace(line), DeclarationsMarker); ok {
+			for _, name := range strings.Fields(list) {
+				names[name] = true
+			}
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String(), names
+}
+
+// splitDeclarations splits TypeScript source into segments. It only knows
+// the structure of the files go2ts writes: a declaration starts at the
+// beginning of a line, with the comment lines right above it, and ends with
+// the line closing its brackets unless the next line continues it, e.g.
+// with "| B".
+func splitDeclarations(src string) []segment {
+	lines := strings.SplitAfter(src, "\n")
+	var segs []segment
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			segs = append(segs, segment{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		// comment lines, attached to a declaration following them
+		start := i
+		inComment := false
+		for i < len(lines) && isCommentLine(lines[i], inComment) {
+			_, inComment = bracketDepth(lines[i], inComment)
+			i++
+		}
+		match := declStart.FindStringSubmatch(lineAt(lines, i))
+		if match == nil {
+			for ; start <= i && start < len(lines); start++ {
+				text.WriteString(lines[start])
+			}
+			i = start
+			continue
+		}
+
+		flush()
+		depth, inComment := 0, false
+		end := i
+		for ; end < len(lines); end++ {
+			var delta int
+			delta, inComment = bracketDepth(lines[end], inComment)
+			depth += delta
+			if depth <= 0 && !inComment && !continues(lines[end], lineAt(lines, end+1)) {
+				break
+			}
+		}
+		end = min(end, len(lines)-1)
+		name := match[1] + match[2]
+		segs = append(segs, segment{name, strings.Join(lines[start:end+1], "")})
+		i = end + 1
+	}
+	flush()
+	return segs
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// isCommentLine reports whether line is only a comment, or a line of a
+// block comment when inComment.
+func isCommentLine(line string, inComment bool) bool {
+	line = strings.TrimSpace(line)
+	return inComment && line != "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*")
+}
+
+// bracketDepth returns the number of brackets line opens minus the number
+// it closes, ignoring those of strings and comments, and whether line ends
+// inside a block comment.
+func bracketDepth(line string, inComment bool) (int, bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		next := byte(0)
+		if i+1 < len(line) {
+			next = line[i+1]
+		}
+		switch {
+		case inComment:
+			if c == '*' && next == '/' {
+				inComment = false
+				i++
+			}
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && next == '/':
+			return depth, false
+		case c == '/' && next == '*':
+			inComment = true
+			i++
+		case c == '{' || c == '(' || c == '[':
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			depth--
+		}
+	}
+	return depth, inComment
+}
+
+// continues reports whether the declaration ending with line goes on with
+// next, as in "export type A =" followed by "  | B".
+func continues(line, next string) bool {
+	line = strings.TrimSpace(line)
+	for _, suffix := range []string{"=", "|", "&", ",", "=>", ":", "extends"} {
+		if strings.HasSuffix(line, suffix) {
+			return true
+		}
+	}
+	next = strings.TrimSpace(next)
+	for _, prefix := range []string{"|", "&", "=", "?", ":", "."} {
+		if strings.HasPrefix(next, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+// SchemaRefPrefix is the base of the $ref of a schema in the OpenAPI output.
+const SchemaRefPrefix = "#/components/schemas/"
+
+// schema is an OpenAPI 3.1 schema object, a JSON Schema 2020-12 dialect.
+type schema struct {
+	Ref                  string        `json:"$ref,omitempty"`
+	Type                 any           `json:"type,omitempty"` // a name, or names for nullable types
+	Format               string        `json:"format,omitempty"`
+	ContentEncoding      string        `json:"contentEncoding,omitempty"`
+	Enum                 []any         `json:"enum,omitempty"`
+	Items                *schema       `json:"items,omitempty"`
+	MinItems             *int          `json:"minItems,omitempty"`
+	MaxItems             *int          `json:"maxItems,omitempty"`
+	Properties           *namedSchemas `json:"properties,omitempty"`
+	Required             []string      `json:"required,omitempty"`
+	AdditionalProperties *schema       `json:"additionalProperties,omitempty"`
+	AllOf                []*schema     `json:"allOf,omitempty"`
+	OneOf                []*schema     `json:"oneOf,omitempty"`
+}
+
+// namedSchemas is a JSON object of schemas keeping its insertion order.
+type namedSchemas struct {
+	names   []string
+	schemas []*schema
+}
+
+func (n *namedSchemas) add(name string, s *schema) {
+	n.names = append(n.names, name)
+	n.schemas = append(n.schemas, s)
+}
+
+func (n *namedSchemas) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range n.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(n.schemas[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// openAPI converts the Go types of the scanned files to schemas.
+type openAPI struct {
+	opts      *Options
+	aliasMap  map[string]string
+	structMap map[string]parser.StructInfo
+	refs      map[string]bool // Go names emitted as components
+	visited   map[string]bool // aliases being expanded
+}
+
+// renderOpenAPI returns an OpenAPI 3.1 document fragment holding a
+// components.schemas entry for every enum and non-generic struct of data.
+// JSON is also valid YAML, so it can be merged into either form of a spec.
+func renderOpenAPI(data parser.GoFileData, opts *Options) (string, error) {
+	c := &openAPI{
+		opts:      opts,
+		aliasMap:  buildAliasMap(data.Aliases),
+		structMap: buildStructMap(data.Structs),
+		refs:      map[string]bool{},
+		visited:   map[string]bool{},
+	}
+	for _, e := range data.Enums {
+		c.refs[e.Name] = true
+	}
+	for _, s := range data.Structs {
+		if len(s.TypeParams) == 0 {
+			c.refs[s.Name] = true
+		}
+	}
+
+	schemas := &namedSchemas{}
+	for _, e := range data.Enums {
+		schemas.add(opts.typeName(e.Name), c.enumSchema(e))
+	}
+	for _, s := range data.Structs {
+		opts.pkg = s.ImportPath
+		if len(s.TypeParams) > 0 {
+			opts.setScope(s.Name)
+			opts.Lossy(s.Name, "generic struct has no OpenAPI schema")
+			continue
+		}
+		schemas.add(opts.typeName(s.Name), c.structSchema(s))
+	}
+
+	doc := map[string]any{"components": map[string]any{"schemas": schemas}}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+func (c *openAPI) enumSchema(e parser.GoEnum) *schema {
+	s := c.schemaOf(e.BaseType)
+	for _, m := range e.Members {
+		if v, err := strconv.Unquote(m.Value); err == nil {
+			s.Enum = append(s.Enum, v)
+		} else if _, err := strconv.ParseFloat(m.Value, 64); err == nil {
+			s.Enum = append(s.Enum, json.Number(m.Value))
+		}
+	}
+	return s
+}
+
+// structSchema returns the object schema of s, combined with the schemas of
+// its embedded structs, whose fields encoding/json promotes, through allOf.
+func (c *openAPI) structSchema(s parser.GoStruct) *schema {
+	obj := &schema{Type: "object", Properties: &namedSchemas{}}
+	for _, sf := range propertyFields(s.Name, s.Fields, nil, map[string]string{}, false, c.aliasMap, c.structMap, c.opts) {
+		f := sf.field
+		name, skip := propertyName(f, c.opts)
+		if skip {
+			continue
+		}
+		c.opts.setScope(s.Name + "." + f.Name)
+		obj.Properties.add(name, c.fieldSchema(f))
+		if jt := ParseJSONTag(f.Tags); !jt.OmitEmpty && !jt.OmitZero && !sf.optional {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+
+	var bases []*schema
+	for _, embed := range s.Embeds {
+		if name := strings.TrimPrefix(embed, "*"); c.refs[name] {
+			bases = append(bases, c.ref(name))
+		}
+	}
+	if len(bases) == 0 {
+		return obj
+	}
+	return &schema{AllOf: append(bases, obj)}
+}
+
+func (c *openAPI) fieldSchema(f parser.StructField) *schema {
+	goType := f.Type
+	if hasDirective(f.Directives, directiveNonNull) {
+		goType = strings.TrimLeft(goType, "*")
+	}
+	s := c.schemaOf(goType)
+	if ParseJSONTag(f.Tags).AsString {
+		s = quotedScalarSchema(s)
+	}
+	if hasDirective(f.Directives, directiveNullable) {
+		s = nullable(s)
+	}
+	return s
+}
+
+// schemaOf returns the schema of the values of a Go type as encoding/json
+// writes them.
+func (c *openAPI) schemaOf(goType string) *schema {
+	goType = strings.TrimSpace(goType)
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return nullable(c.schemaOf(goType[len("*"):]))
+	case goType == "[]byte":
+		return &schema{Type: "string", ContentEncoding: "base64"}
+	case strings.HasPrefix(goType, "[]"):
+		return &schema{Type: "array", Items: c.schemaOf(goType[len("[]"):])}
+	case strings.HasPrefix(goType, "map["):
+		if _, value, ok := strings.Cut(goType, "]"); ok {
+			return &schema{Type: "object", AdditionalProperties: c.schemaOf(value)}
+		}
+	case strings.HasPrefix(goType, "["):
+		return c.arraySchema(goType)
+	case strings.HasPrefix(goType, "struct{"):
+		c.opts.Lossy(goType, "inline struct type converted to an untyped object schema")
+		return &schema{Type: "object"}
+	}
+
+	if s, ok := scalarSchema(goType); ok {
+		return s
+	}
+	if c.refs[goType] {
+		return c.ref(goType)
+	}
+	if underlying, ok := c.aliasMap[goType]; ok && !c.visited[goType] {
+		c.visited[goType] = true
+		defer delete(c.visited, goType)
+		return c.schemaOf(underlying)
+	}
+
+	// known and special types, through their TypeScript mapping
+	ts := parser.GoTypeToTSTypeWithConfig(goType, c.aliasMap, nil, c.structMap, map[string]string{}, map[string]bool{}, &c.opts.Config)
+	switch ts {
+	case "string", "number", "boolean":
+		return &schema{Type: ts}
+	case "any", "unknown", parser.UnresolvedType:
+		// exact, or reported by the mapping already
+	default:
+		c.opts.Lossy(goType, "%s has no OpenAPI schema, converted to an empty schema", ts)
+	}
+	return &schema{}
+}
+
+// arraySchema returns the schema of a fixed-length array type such as
+// [3]float64. Byte arrays map to strings, as in the TypeScript output.
+func (c *openAPI) arraySchema(goType string) *schema {
+	length, elem, _ := strings.Cut(goType[len("["):], "]")
+	if elem == "byte" || elem == "uint8" {
+		if !c.opts.ByteArraysAsNumbers {
+			return &schema{Type: "string"}
+		}
+	}
+	s := &schema{Type: "array", Items: c.schemaOf(elem)}
+	if n, err := strconv.Atoi(length); err == nil {
+		s.MinItems, s.MaxItems = &n, &n
+	}
+	return s
+}
+
+func (c *openAPI) ref(name string) *schema {
+	return &schema{Ref: SchemaRefPrefix + c.opts.typeName(name)}
+}
+
+// scalarSchema returns the schema of the Go basic types whose JSON form
+// JSON Schema describes more precisely than the TypeScript mapping.
+func scalarSchema(goType string) (*schema, bool) {
+	switch goType {
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte":
+		return &schema{Type: "integer"}, true
+	case "int32", "uint32", "rune":
+		return &schema{Type: "integer", Format: "int32"}, true
+	case "int64", "uint64":
+		return &schema{Type: "integer", Format: "int64"}, true
+	case "float32":
+		return &schema{Type: "number", Format: "float"}, true
+	case "float64":
+		return &schema{Type: "number", Format: "double"}, true
+	case "time.Time":
+		return &schema{Type: "string", Format: "date-time"}, true
+	case "uuid.UUID":
+		return &schema{Type: "string", Format: "uuid"}, true
+	case "url.URL":
+		return &schema{Type: "string", Format: "uri"}, true
+	}
+	return nil, false
+}
+
+// quotedScalarSchema returns the schema of a value of schema s encoded with
+// the ",string" option, which quotes numbers and booleans.
+func quotedScalarSchema(s *schema) *schema {
+	switch t := s.Type.(type) {
+	case string:
+		if t == "integer" || t == "number" || t == "boolean" {
+			return &schema{Type: "string"}
+		}
+	case []string:
+		if t[0] == "integer" || t[0] == "number" || t[0] == "boolean" {
+			return &schema{Type: []string{"string", "null"}}
+		}
+	}
+	return s
+}
+
+// nullable returns s also admitting null, the OpenAPI 3.1 way: a type list
+// for typed schemas, oneOf with the null type for references.
+func nullable(s *schema) *schema {
+	switch t := s.Type.(type) {
+	case string:
+		s.Type = []string{t, "null"}
+		return s
+	case []string:
+		return s
+	}
+	if s.Ref == "" && s.AllOf == nil && s.OneOf == nil {
+		return s // the empty schema admits null already
+	}
+	return &schema{OneOf: []*schema{s, {Type: "null"}}}
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// quote returns s as a TypeScript string literal in the configured quote style.
+func (o *Options) quote(s string) string {
+	q := strconv.Quote(s)
+	if !o.SingleQuote {
+		return q
+	}
+	inner := strings.ReplaceAll(q[1:len(q)-1], `\"`, `"`)
+	return "'" + strings.ReplaceAll(inner, "'", `\'`) + "'"
+}
+
+// literal requotes a literal value parsed from Go, such as an enum member,
+// in the configured quote style. Other literals are returned unchanged.
+func (o *Options) literal(value string) string {
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return o.quote(s)
+	}
+	return value
+}
+
+// literalKey returns the literal value as an object key: negative numbers
+// are not valid keys and are computed instead, e.g. [-1].
+func (o *Options) literalKey(value string) string {
+	key := o.literal(value)
+	if strings.HasPrefix(key, "-") {
+		return "[" + key + "]"
+	}
+	return key
+}
+
+// propertyKey returns name as a property key: as-is when it is an
+// identifier, and quoted otherwise, e.g. "first-name" or "-" from tags.
+func (o *Options) propertyKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return o.quote(name)
+}
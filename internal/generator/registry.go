@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+// generateRegistryTS renders the union of every non-generic struct, named by
+// opts.Registry, and the union of their Go names, named <Registry>Name:
+//
+//	export type AnyModel = UserAccount | SalesOrder;
+//	export type AnyModelName = "UserAccount" | "SalesOrder";
+func generateRegistryTS(structs []parser.GoStruct, opts *Options) string {
+	var types, names []string
+	for _, s := range structs {
+		if len(s.TypeParams) > 0 {
+			continue
+		}
+		types = append(types, opts.typeName(s.Name))
+		names = append(names, opts.quote(s.Name))
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("export type %s = %s;\n\nexport type %sName = %s;\n\n",
+		opts.Registry, strings.Join(types, " | "), opts.Registry, strings.Join(names, " | "))
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// typeName returns the emitted name of a declared type, with TypePrefix and
+// TypeSuffix applied.
+func (o *Options) typeName(name string) string {
+	return o.TypePrefix + name + o.TypeSuffix
+}
+
+// renameRefs applies TypePrefix and TypeSuffix to the references to declared
+// types in the TypeScript type expression ts. Property names of inline object
+// types are left alone, as are names that are not declared in the output and
+// typeParams, which shadow declared names.
+func (o *Options) renameRefs(ts string, typeParams []string) string {
+	if o.TypePrefix == "" && o.TypeSuffix == "" {
+		return ts
+	}
+	return mapTypeIdents(ts, func(ident string) string {
+		if o.declared[ident] && !slices.Contains(typeParams, ident) {
+			return o.typeName(ident)
+		}
+		return ident
+	})
+}
+
+// mapTypeIdents replaces every identifier of the TypeScript type expression
+// ts by fn(identifier), except property names of inline object types and the
+// parts of qualified names after the first.
+func mapTypeIdents(ts string, fn func(ident string) string) string {
+	var sb strings.Builder
+	for i := 0; i < len(ts); {
+		if !isIdentStart(ts[i]) || (i > 0 && (isIdentPart(ts[i-1]) || ts[i-1] == '.')) {
+			sb.WriteByte(ts[i])
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(ts) && isIdentPart(ts[j]) {
+			j++
+		}
+		ident := ts[i:j]
+		if !isPropertyKey(ts[j:]) {
+			ident = fn(ident)
+		}
+		sb.WriteString(ident)
+		i = j
+	}
+	return sb.String()
+}
+
+// isPropertyKey reports whether an identifier followed by rest is the key of
+// an object type member, e.g. "Name: string" or "Name?: string".
+func isPropertyKey(rest string) bool {
+	rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	return strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "?:")
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,51 @@
+package generator
+
+import "strings"
+
+// Separator selects the character separating the members of object types.
+type Separator int
+
+const (
+	// SeparatorSemicolon ends interface properties with a semicolon and
+	// separates the members of inline object types with "; ".
+	SeparatorSemicolon Separator = iota
+	// SeparatorComma ends interface properties with a comma and separates
+	// the members of inline object types with ", ".
+	SeparatorComma
+	// SeparatorNone leaves interface properties, one per line, without a
+	// separator. Inline object types, written on one line, keep "; ".
+	SeparatorNone
+)
+
+// memberEnd returns the text ending an interface property.
+func (o *Options) memberEnd() string {
+	switch o.Separator {
+	case SeparatorComma:
+		return ","
+	case SeparatorNone:
+		return ""
+	}
+	return ";"
+}
+
+// separateMembers rewrites the semicolons separating the members of the
+// inline object types of ts, e.g. "{ X: number; Y: number }", with the
+// configured separator. String literals are left untouched.
+func (o *Options) separateMembers(ts string) string {
+	if o.Separator != SeparatorComma || !strings.Contains(ts, ";") {
+		return ts
+	}
+	b := []byte(ts)
+	depth := 0
+	scanCode(ts, func(i int, c byte) {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == ';' && depth > 0:
+			b[i] = ','
+		}
+	})
+	return string(b)
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/limbicnode/go2ts/internal/parser"
+)
+
+const defaultOneOfDiscriminant = "Type"
+
+// interfaceUnions maps each interface to the non-generic structs whose method
+// set contains every method of the interface, in declaration order.
+// Methods are matched by name only; interfaces without implementers are omitted.
+func interfaceUnions(data parser.GoFileData) map[string][]string {
+	unions := map[string][]string{}
+	for _, iface := range data.Interfaces {
+		var members []string
+		for _, s := range data.Structs {
+			if len(s.TypeParams) > 0 || !hasMethods(s.Methods, iface.Methods) {
+				continue
+			}
+			members = append(members, s.Name)
+		}
+		if len(members) > 0 {
+			unions[iface.Name] = members
+		}
+	}
+	return unions
+}
+
+func hasMethods(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, m := range have {
+		set[m] = true
+	}
+	for _, m := range want {
+		if !set[m] {
+			return false
+		}
+	}
+	return true
+}
+
+// discriminatedUnion describes a struct following the oneof convention.
+type discriminatedUnion struct {
+	Discriminant string // JSON name of the discriminant property
+	Variants     []unionVariant
+	Common       []scopedField // fields shared by every variant
+}
+
+// unionVariant is one member of a discriminated union.
+type unionVariant struct {
+	Value string // discriminant value, the JSON name of the payload field
+	Field scopedField
+}
+
+// detectOneOf reports whether s has a string field named after the configured
+// discriminant and at least two pointer fields to known structs, which become
+// the union variants.
+func detectOneOf(s parser.GoStruct,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	opts *Options) (discriminatedUnion, bool) {
+	discriminant := opts.OneOfDiscriminant
+	if discriminant == "" {
+		discriminant = defaultOneOfDiscriminant
+	}
+	if len(s.TypeParams) > 0 {
+		return discriminatedUnion{}, false
+	}
+
+	var u discriminatedUnion
+	fields := propertyFields(s.Name, s.Fields, nil, map[string]string{}, false, aliasMap, structMap, opts)
+	for _, sf := range sortFields(fields, opts) {
+		f := sf.field
+		name, skip := propertyName(f, opts)
+		switch {
+		case skip:
+			continue
+		case f.Name == discriminant && f.Type == "string":
+			u.Discriminant = name
+		case strings.HasPrefix(f.Type, "*") && parser.IsUserDefinedStruct(f.Type[1:], structMap):
+			u.Variants = append(u.Variants, unionVariant{Value: name, Field: sf})
+		default:
+			u.Common = append(u.Common, sf)
+		}
+	}
+
+	const minVariants = 2
+	if u.Discriminant == "" || len(u.Variants) < minVariants {
+		return discriminatedUnion{}, false
+	}
+	return u, true
+}
+
+// generateOneOfTS renders a discriminated union, one object type per variant:
+//
+//	export type Webhook =
+//	  | { type: "foo"; foo: FooData; id: string }
+//	  | { type: "bar"; bar: BarData; id: string };
+func generateOneOfTS(s parser.GoStruct,
+	u discriminatedUnion,
+	aliasMap map[string]string,
+	structMap map[string]parser.StructInfo,
+	opts *Options) string {
+	var common []string
+	for _, sf := range u.Common {
+		opts.setScope(s.Name + "." + sf.field.Name)
+		p := fieldProperty(sf.field, aliasMap, sf.typeParams, structMap, sf.typeParamMapping, opts)
+		p.Optional = p.Optional || sf.optional
+		common = append(common, p.String())
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("export type %s =\n", opts.typeName(s.Name)))
+	for i, v := range u.Variants {
+		payload := v.Field.field
+		payload.Type = strings.TrimPrefix(payload.Type, "*")
+		opts.setScope(s.Name + "." + payload.Name)
+
+		// the payload is always present in its own variant
+		prop := fieldProperty(payload, aliasMap, v.Field.typeParams, structMap, v.Field.typeParamMapping, opts)
+		prop.Optional = false
+
+		props := []string{opts.propertyKey(u.Discriminant) + ": " + opts.quote(v.Value), prop.String()}
+		props = append(props, common...)
+
+		sb.WriteString(opts.separateMembers("  | { " + strings.Join(props, "; ") + " }"))
+		if i == len(u.Variants)-1 {
+			sb.WriteString(";")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// generateTypeGuardsTS renders a type guard for every variant of u:
+//
+//	export function isWebhookFoo(v: Webhook): v is Extract<Webhook, { type: "foo" }> {
+//	  return v.type === "foo";
+//	}
+func generateTypeGuardsTS(s parser.GoStruct, u discriminatedUnion, opts *Options) string {
+	name := opts.typeName(s.Name)
+	key := opts.propertyKey(u.Discriminant)
+	access := "v." + key
+	if key != u.Discriminant {
+		access = "v[" + key + "]"
+	}
+	var sb strings.Builder
+	for _, v := range u.Variants {
+		value := opts.quote(v.Value)
+		sb.WriteString(fmt.Sprintf("export function is%s%s(v: %s): v is Extract<%s, { %s: %s }> {\n",
+			name, v.Field.field.Name, name, name, key, value))
+		sb.WriteString(fmt.Sprintf("  return %s === %s;\n}\n\n", access, value))
+	}
+	return sb.String()
+}
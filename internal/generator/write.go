@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const outputMode = 0o644 // mode of newly created output files
+
+// writeAtomic writes content to path through a temporary file in the same
+// directory renamed over path, so readers never see a partially written
+// file. An existing file keeps its mode, and a symlink is followed so the
+// file it points to is replaced rather than the link.
+func writeAtomic(path, content string) (err error) {
+	if target, evalErr := filepath.EvalSymlinks(path); evalErr == nil {
+		path = target
+	}
+	mode := os.FileMode(outputMode)
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.WriteString(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ParseGoDirs parses the Go files of every directory of dirs like ParseGoFiles
+// and merges the results into one.
+func ParseGoDirs(dirs []string) (GoFileData, error) {
+	return ParseGoDirsWithOptions(dirs, ParseGoFilesOptions{})
+}
+
+// ParseGoDirsWithOptions parses the Go files of every directory of dirs like
+// ParseGoFilesWithOptions and merges the results, in the order of dirs. A
+// type found in several directories is kept once; declared differently, it
+// is an error naming both directories, as is a name declared as a struct,
+// an alias or an interface in one directory and as another kind in the next.
+func ParseGoDirsWithOptions(dirs []string, opts ParseGoFilesOptions) (GoFileData, error) {
+	var merged GoFileData
+	structs := map[string]int{} // index in merged.Structs
+	aliases := map[string]int{}
+	interfaces := map[string]int{}
+	enums := map[string]int{}
+	origin := map[string]string{} // directory declaring each type
+	kinds := map[string]string{}  // kinds each type is declared as
+
+	for _, dir := range dirs {
+		data, err := ParseGoFilesWithOptions(dir, opts)
+		if err != nil {
+			return GoFileData{}, err
+		}
+		conflict := func(kind, name string) error {
+			return fmt.Errorf("%s %q is declared differently in %s and %s", kind, name, origin[name], dir)
+		}
+		for name, kind := range typeKinds(data) {
+			if prev, ok := kinds[name]; ok && prev != kind {
+				return GoFileData{}, conflict("type", name)
+			}
+			kinds[name] = kind
+		}
+
+		for _, s := range data.Structs {
+			if i, ok := structs[s.Name]; ok {
+				if !sameStruct(merged.Structs[i], s) {
+					return GoFileData{}, conflict("struct", s.Name)
+				}
+				continue
+			}
+			structs[s.Name] = len(merged.Structs)
+			merged.Structs = append(merged.Structs, s)
+			origin[s.Name] = dir
+		}
+		for _, a := range data.Aliases {
+			if i, ok := aliases[a.Name]; ok {
+				if prev := merged.Aliases[i]; prev.Underlying != a.Underlying || !slices.Equal(prev.TypeParams, a.TypeParams) {
+					return GoFileData{}, conflict("type", a.Name)
+				}
+				continue
+			}
+			aliases[a.Name] = len(merged.Aliases)
+			merged.Aliases = append(merged.Aliases, a)
+			origin[a.Name] = dir
+		}
+		for _, iface := range data.Interfaces {
+			if i, ok := interfaces[iface.Name]; ok {
+				if !slices.Equal(merged.Interfaces[i].Methods, iface.Methods) {
+					return GoFileData{}, conflict("interface", iface.Name)
+				}
+				continue
+			}
+			interfaces[iface.Name] = len(merged.Interfaces)
+			merged.Interfaces = append(merged.Interfaces, iface)
+			origin[iface.Name] = dir
+		}
+		for _, e := range data.Enums {
+			if i, ok := enums[e.Name]; ok {
+				if !reflect.DeepEqual(merged.Enums[i].Members, e.Members) {
+					return GoFileData{}, conflict("enum", e.Name)
+				}
+				continue
+			}
+			enums[e.Name] = len(merged.Enums)
+			merged.Enums = append(merged.Enums, e)
+		}
+	}
+	return merged, nil
+}
+
+// typeKinds returns the kinds of declaration of every type of data, e.g.
+// "alias,interface" for an interface, which is also recorded as an alias.
+func typeKinds(data GoFileData) map[string]string {
+	kinds := map[string][]string{}
+	for _, s := range data.Structs {
+		kinds[s.Name] = append(kinds[s.Name], "struct")
+	}
+	for _, a := range data.Aliases {
+		kinds[a.Name] = append(kinds[a.Name], "alias")
+	}
+	for _, iface := range data.Interfaces {
+		kinds[iface.Name] = append(kinds[iface.Name], "interface")
+	}
+	joined := make(map[string]string, len(kinds))
+	for name, k := range kinds {
+		joined[name] = strings.Join(slices.Compact(k), ",")
+	}
+	return joined
+}
+
+// sameStruct reports whether a and b declare the same fields, type
+// parameters and embedded types. Comments and positions may differ.
+func sameStruct(a, b GoStruct) bool {
+	if len(a.Fields) != len(b.Fields) || !slices.Equal(a.TypeParams, b.TypeParams) || !slices.Equal(a.Embeds, b.Embeds) {
+		return false
+	}
+	for i, f := range a.Fields {
+		g := b.Fields[i]
+		if f.Name != g.Name || f.Type != g.Type || f.Tags != g.Tags {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// GoEnum is a declared type together with the constants declared of it,
+// e.g. "type OrderStatus int" and its iota block.
+type GoEnum struct {
+	Name     string // the named type, e.g. "OrderStatus"
+	BaseType string // underlying type of the named type, e.g. "int"
+	Members  []EnumMember
+}
+
+// EnumMember is one constant of an enum.
+type EnumMember struct {
+	Name  string // constant name, e.g. "OrderPending"
+	Value string // TypeScript literal of the value, e.g. "0" or "\"red\""
+}
+
+// enumsOf returns the enums of the declared, non-struct types in aliases, in
+// the order their constants were first declared.
+func enumsOf(aliases []TypeAlias, consts map[string][]EnumMember, order []string) []GoEnum {
+	underlying := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		if alias.Defined && len(alias.TypeParams) == 0 {
+			underlying[alias.Name] = alias.Underlying
+		}
+	}
+
+	var enums []GoEnum
+	for _, name := range order {
+		base, ok := underlying[name]
+		if !ok {
+			continue
+		}
+		enums = append(enums, GoEnum{Name: name, BaseType: base, Members: consts[name]})
+	}
+	return enums
+}
+
+// collectEnumConsts appends the typed constants of decl to enums, keyed by
+// type name, recording first appearances in order. Constants whose value
+// cannot be evaluated (anything beyond literals, iota and integer arithmetic)
+// are left out.
+func collectEnumConsts(decl *ast.GenDecl, enums map[string][]EnumMember, order *[]string) {
+	var typeName string
+	var values []ast.Expr
+	for index, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		// a spec without values repeats the type and expressions of the previous one
+		if len(valueSpec.Values) > 0 {
+			typeName, values = "", valueSpec.Values
+			if ident, isIdent := valueSpec.Type.(*ast.Ident); isIdent {
+				typeName = ident.Name
+			}
+		} else if valueSpec.Type != nil {
+			continue
+		}
+
+		for i, name := range valueSpec.Names {
+			if name.Name == "_" || i >= len(values) {
+				continue
+			}
+			expr, memberType := unwrapConversion(values[i], typeName)
+			if memberType == "" {
+				continue
+			}
+			value, ok := constValue(expr, int64(index))
+			if !ok {
+				continue
+			}
+			if _, seen := enums[memberType]; !seen {
+				*order = append(*order, memberType)
+			}
+			enums[memberType] = append(enums[memberType], EnumMember{Name: name.Name, Value: value})
+		}
+	}
+}
+
+// unwrapConversion strips a conversion to a named type, e.g. Color("red"),
+// returning the converted expression and the type of the constant.
+func unwrapConversion(expr ast.Expr, typeName string) (ast.Expr, string) {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if ident, isIdent := call.Fun.(*ast.Ident); isIdent && (typeName == "" || typeName == ident.Name) {
+			return call.Args[0], ident.Name
+		}
+	}
+	return expr, typeName
+}
+
+// constValue evaluates a constant expression to a TypeScript literal, with
+// iota standing for the index of the spec in its declaration.
+func constValue(expr ast.Expr, iota int64) (string, bool) {
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return "", false
+		}
+		return strconv.Quote(s), true
+	}
+	n, ok := intValue(expr, iota)
+	if !ok {
+		return "", false
+	}
+	return strconv.FormatInt(n, 10), true
+}
+
+func intValue(expr ast.Expr, iota int64) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT {
+			return 0, false
+		}
+		n, err := strconv.ParseInt(e.Value, 0, 64)
+		return n, err == nil
+	case *ast.Ident:
+		return iota, e.Name == "iota"
+	case *ast.ParenExpr:
+		return intValue(e.X, iota)
+	case *ast.UnaryExpr:
+		x, ok := intValue(e.X, iota)
+		switch e.Op {
+		case token.SUB:
+			return -x, ok
+		case token.ADD:
+			return x, ok
+		default:
+			return 0, false
+		}
+	case *ast.BinaryExpr:
+		x, okX := intValue(e.X, iota)
+		y, okY := intValue(e.Y, iota)
+		if !okX || !okY {
+			return 0, false
+		}
+		return binaryOp(e.Op, x, y)
+	}
+	return 0, false
+}
+
+func binaryOp(op token.Token, x, y int64) (int64, bool) {
+	switch op {
+	case token.ADD:
+		return x + y, true
+	case token.SUB:
+		return x - y, true
+	case token.MUL:
+		return x * y, true
+	case token.QUO:
+		return x / y, y != 0
+	case token.REM:
+		return x % y, y != 0
+	case token.SHL:
+		return x << y, y >= 0
+	case token.SHR:
+		return x >> y, y >= 0
+	case token.OR:
+		return x | y, true
+	case token.AND:
+		return x & y, true
+	case token.XOR:
+		return x ^ y, true
+	}
+	return 0, false
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"go/ast"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// funcTypeString renders a function type with its signature, e.g.
+// "func(id int, opts ...string) (string, error)". Parameter names are kept,
+// one per parameter; result names are dropped.
+func funcTypeString(t *ast.FuncType) string {
+	if t.Params == nil {
+		return "func"
+	}
+	var params []string
+	for _, field := range t.Params.List {
+		typ := ExprToString(field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, typ)
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, name.Name+" "+typ)
+		}
+	}
+
+	var results []string
+	if t.Results != nil {
+		for _, field := range t.Results.List {
+			for range max(len(field.Names), 1) {
+				results = append(results, ExprToString(field.Type))
+			}
+		}
+	}
+
+	s := "func(" + strings.Join(params, ", ") + ")"
+	switch len(results) {
+	case 0:
+		return s
+	case 1:
+		return s + " " + results[0]
+	default:
+		return s + " (" + strings.Join(results, ", ") + ")"
+	}
+}
+
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]* `)
+
+// funcType converts a function signature, e.g. "func(id int) (string, error)",
+// to a TypeScript function type, "(id: number) => [string, Error]". Unnamed
+// parameters are named arg, or arg0, arg1, ... when there are several.
+func funcType(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
+) string {
+	convert := func(t string) string {
+		ts := GoTypeToTSTypeWithConfig(t, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		return cfg.Resolved(ts, t)
+	}
+
+	paramList, resultList := splitSignature(goType[len("func"):])
+	params := splitTopLevel(paramList)
+	tsParams := make([]string, 0, len(params))
+	for i, p := range params {
+		name := "arg"
+		if len(params) > 1 {
+			name += strconv.Itoa(i)
+		}
+		if loc := paramNamePattern.FindStringIndex(p); loc != nil && !strings.HasPrefix(p, "chan ") {
+			name, p = strings.TrimSpace(p[:loc[1]]), p[loc[1]:]
+		}
+		if strings.HasPrefix(p, "...") {
+			tsParams = append(tsParams, "..."+name+": "+convert("[]"+p[len("..."):]))
+			continue
+		}
+		tsParams = append(tsParams, name+": "+convert(p))
+	}
+
+	results := splitTopLevel(strings.TrimSuffix(strings.TrimPrefix(resultList, "("), ")"))
+	tsResults := make([]string, 0, len(results))
+	for _, r := range results {
+		tsResults = append(tsResults, convert(r))
+	}
+	ret := "void"
+	switch {
+	case len(tsResults) == 1:
+		ret = tsResults[0]
+	case len(tsResults) > 1:
+		ret = "[" + strings.Join(tsResults, ", ") + "]"
+	}
+	return "(" + strings.Join(tsParams, ", ") + ") => " + ret
+}
+
+// splitSignature splits "(params) results" at the parenthesis closing the
+// parameter list.
+func splitSignature(sig string) (params, results string) {
+	depth := 0
+	for i, r := range sig {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 && r == ')' {
+				return sig[1:i], strings.TrimSpace(sig[i+1:])
+			}
+		}
+	}
+	return "", ""
+}
+
+// splitTopLevel splits a comma-separated list of Go types at the commas that
+// are not nested in brackets, dropping empty entries.
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, list[start:])
+
+	kept := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// hasTopLevelArrow reports whether ts is a function type outside of any
+// grouping, e.g. "(id: number) => string", which must be parenthesized
+// before "[]" or "| null" is added.
+func hasTopLevelArrow(ts string) bool {
+	depth := 0
+	for i, r := range ts {
+		switch r {
+		case '(', '{', '<', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case '>':
+			if i > 0 && ts[i-1] == '=' {
+				if depth == 0 {
+					return true
+				}
+				continue
+			}
+			depth--
+		}
+	}
+	return false
+}
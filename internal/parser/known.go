@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// PresetMongo returns the mappings of the MongoDB driver's BSON types.
+func PresetMongo() map[string]string {
+	return map[string]string{
+		"primitive.ObjectID":   "string",
+		"primitive.Decimal128": "string",
+		"primitive.DateTime":   "string",
+		"primitive.Timestamp":  "number",
+		"primitive.Binary":     "Uint8Array",
+	}
+}
+
+// PresetSQL returns the mappings of the database/sql null types and of the
+// PostgreSQL driver types.
+func PresetSQL() map[string]string {
+	return map[string]string{
+		"sql.NullString": "string | null",
+		"sql.NullInt64":  "number | null",
+		"sql.NullBool":   "boolean | null",
+		"pq.NullTime":    "string | null",
+		"pgtype.UUID":    "string",
+	}
+}
+
+// PresetCommon returns the mappings of widely used value types that marshal
+// to JSON strings, such as decimal.Decimal and uuid.UUID.
+func PresetCommon() map[string]string {
+	return map[string]string{
+		"decimal.Decimal": "string",
+		"uuid.UUID":       "string",
+	}
+}
+
+var (
+	knownTypesMu sync.RWMutex
+	knownTypes   = presets(PresetMongo(), PresetSQL(), PresetCommon())
+)
+
+func presets(groups ...map[string]string) map[string]string {
+	all := map[string]string{}
+	for _, group := range groups {
+		maps.Copy(all, group)
+	}
+	return all
+}
+
+// RegisterTypeMapping maps goType, e.g. "money.Amount", to tsType for every
+// conversion, replacing any earlier mapping of goType. Config.Overrides still
+// take precedence.
+func RegisterTypeMapping(goType, tsType string) {
+	knownTypesMu.Lock()
+	defer knownTypesMu.Unlock()
+	knownTypes[goType] = tsType
+}
+
+// RegisterTypeMappings registers every mapping of m, e.g. a preset.
+func RegisterTypeMappings(m map[string]string) {
+	knownTypesMu.Lock()
+	defer knownTypesMu.Unlock()
+	maps.Copy(knownTypes, m)
+}
+
+// UnregisterTypeMapping removes the mapping of goType, a preset one
+// included, so that it converts like an unknown type again.
+func UnregisterTypeMapping(goType string) {
+	knownTypesMu.Lock()
+	defer knownTypesMu.Unlock()
+	delete(knownTypes, goType)
+}
+
+// KnownTypeMappings returns a copy of the registered mappings, including the
+// presets registered by default.
+func KnownTypeMappings() map[string]string {
+	knownTypesMu.RLock()
+	defer knownTypesMu.RUnlock()
+	return maps.Clone(knownTypes)
+}
+
+func knownType(goType string) (string, bool) {
+	knownTypesMu.RLock()
+	defer knownTypesMu.RUnlock()
+	ts, ok := knownTypes[goType]
+	return ts, ok
+}
+
+// ParseOverrides parses a comma-separated list of goType=tsType mappings for
+// Config.Overrides, e.g. "money.Amount=string,geo.Point=GeoPoint". A comma
+// nested in brackets or followed by no "=" belongs to the TypeScript type,
+// as in "geo.Point={ lat: number, lng: number }" or "Meta=Record<string, any>".
+func ParseOverrides(spec string) (map[string]string, error) {
+	var pairs []string
+	for _, part := range splitTopLevel(spec) {
+		if len(pairs) > 0 && !strings.Contains(part, "=") {
+			pairs[len(pairs)-1] += ", " + part
+			continue
+		}
+		pairs = append(pairs, part)
+	}
+
+	overrides := map[string]string{}
+	for _, pair := range pairs {
+		goType, tsType, ok := strings.Cut(pair, "=")
+		goType, tsType = strings.TrimSpace(goType), strings.TrimSpace(tsType)
+		if !ok || goType == "" || tsType == "" {
+			return nil, fmt.Errorf("invalid override %q: want goType=tsType", pair)
+		}
+		overrides[goType] = tsType
+	}
+	return overrides, nil
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// moduleDownload mirrors the fields of `go mod download -json` used here.
+type moduleDownload struct {
+	Path    string
+	Version string
+	Dir     string
+	Error   string
+}
+
+// DownloadModule - fetches a Go module through the module proxy and returns
+// the directory of its source in the module cache.
+// The module is given as "path@version"; a missing version defaults to "latest".
+func DownloadModule(module string) (string, error) {
+	module = strings.TrimSpace(module)
+	if module == "" || strings.HasPrefix(module, "@") {
+		return "", errors.New("module path must not be empty")
+	}
+	if !strings.Contains(module, "@") {
+		module += "@latest"
+	}
+
+	// Run outside of any main module so the current go.mod is left untouched.
+	cmd := exec.CommandContext(context.Background(), "go", "mod", "download", "-json", module) //nolint:gosec // module is an explicit user input
+	cmd.Dir = os.TempDir()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var info moduleDownload
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		if runErr != nil {
+			return "", fmt.Errorf("go mod download %s: %w: %s", module, runErr, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("go mod download %s: invalid output: %w", module, err)
+	}
+	if info.Error != "" {
+		return "", fmt.Errorf("go mod download %s: %s", module, info.Error)
+	}
+	if runErr != nil {
+		return "", fmt.Errorf("go mod download %s: %w", module, runErr)
+	}
+	if info.Dir == "" {
+		return "", fmt.Errorf("go mod download %s: no source directory reported", module)
+	}
+	return info.Dir, nil
+}
+
+// IsImportPath reports whether in names a Go package by import path, e.g.
+// "github.com/me/proj/internal/model", rather than a directory: it is not an
+// existing directory, and its first element has a dot, as a domain name
+// has, or it lies in the module of the current directory. Anything else is
+// taken for a directory, so a mistyped one is reported as missing.
+func IsImportPath(in string) bool {
+	if in == "" || filepath.IsAbs(in) || in == "." || in == ".." ||
+		strings.HasPrefix(in, "./") || strings.HasPrefix(in, "../") {
+		return false
+	}
+	if info, err := os.Stat(in); err == nil && info.IsDir() {
+		return false
+	}
+	first, _, _ := strings.Cut(in, "/")
+	if strings.Contains(first, ".") {
+		return true
+	}
+	module := currentModule()
+	return module != "" && (in == module || strings.HasPrefix(in, module+"/"))
+}
+
+// currentModule returns the path of the module enclosing the current
+// directory, or "" outside of a module.
+func currentModule() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for root := wd; ; root = filepath.Dir(root) {
+		if module := modulePath(filepath.Join(root, "go.mod")); module != "" {
+			return module
+		}
+		if filepath.Dir(root) == root {
+			return ""
+		}
+	}
+}
+
+// PackageDir - resolves a Go import path to the directory of its source with
+// `go list`, as seen from the current module.
+func PackageDir(importPath string) (string, error) {
+	cmd := exec.CommandContext(context.Background(), "go", "list", "-f", "{{.Dir}}", importPath) //nolint:gosec // importPath is an explicit user input
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go list %s: %w: %s", importPath, err, strings.TrimSpace(stderr.String()))
+	}
+	dir := strings.TrimSpace(stdout.String())
+	if dir == "" {
+		return "", fmt.Errorf("go list %s: no source directory reported", importPath)
+	}
+	return dir, nil
+}
+
+// goPackage is the Go package declaring the types of a file.
+type goPackage struct {
+	name string // package clause, e.g. "model"
+	path string // import path, e.g. "github.com/me/api/model"
+}
+
+// importPathCache resolves the import paths of package directories, keyed by
+// directory.
+type importPathCache map[string]string
+
+// of returns the import path of the package in dir: the path of the
+// enclosing module joined with the directory relative to the module root.
+// Outside of a module it is the slash-separated directory.
+func (c importPathCache) of(dir string) string {
+	if importPath, ok := c[dir]; ok {
+		return importPath
+	}
+	importPath := filepath.ToSlash(filepath.Clean(dir))
+	if abs, err := filepath.Abs(dir); err == nil {
+		for root := abs; ; root = filepath.Dir(root) {
+			if module := modulePath(filepath.Join(root, "go.mod")); module != "" {
+				rel, _ := filepath.Rel(root, abs)
+				importPath = path.Join(module, filepath.ToSlash(rel))
+				break
+			}
+			if filepath.Dir(root) == root {
+				break
+			}
+		}
+	}
+	c[dir] = importPath
+	return importPath
+}
+
+// modulePath returns the module path declared by the go.mod file at
+// gomod, or "" when there is none.
+func modulePath(gomod string) string {
+	content, err := os.ReadFile(gomod) //nolint:gosec // go.mod of a scanned directory
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		module, _, _ := strings.Cut(strings.TrimSpace(rest), "//")
+		module = strings.TrimSpace(module)
+		if unquoted, err := strconv.Unquote(module); err == nil {
+			module = unquoted
+		}
+		return module
+	}
+	return ""
+}
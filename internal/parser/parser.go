@@ -8,35 +8,61 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 )
 
 // StructField represents a field in a Go struct.
 type StructField struct {
-	Name string
-	Type string
-	Tags string
+	Name       string
+	Type       string
+	Tags       string
+	Directives []string // go2ts comment directives, e.g. "nullable" for //go2ts:nullable
+	Doc        string   // text of the doc comment above the field, without directives
+	Comment    string   // text of the line comment after the field, e.g. "years"
 }
 
 // GoStruct represents a Go struct definition.
 type GoStruct struct {
-	Name       string
-	Fields     []StructField
-	TypeParams []string // generic type parameters
+	Name        string
+	Fields      []StructField
+	TypeParams  []string // generic type parameters
+	Constraints []string // constraint of each type parameter, e.g. "any"
+	Methods     []string // names of methods declared on the type or its pointer
+	Marshaler   bool     // declares MarshalJSON() ([]byte, error), so its JSON may not follow its fields
+	Embeds      []string // types embedded without a JSON name, e.g. "*Base", whose fields are promoted
+	Package     string   // name of the declaring Go package
+	ImportPath  string   // import path of the declaring package, or its directory outside of a module
+	Doc         string   // text of the doc comment of the type, without directives
+	Pos         token.Position
+}
+
+// GoInterface represents a Go interface definition and its method names.
+type GoInterface struct {
+	Name    string
+	Methods []string
 }
 
 // TypeAlias represents a Go type alias definition.
 type TypeAlias struct {
-	Name       string
-	TypeParams []string // generic type parameters names
-	Underlying string   // underlying type expression as string
+	Name        string
+	TypeParams  []string // generic type parameters names
+	Constraints []string // constraint of each type parameter, e.g. "any"
+	Underlying  string   // underlying type expression as string
+	Defined     bool     // defined type ("type X int") rather than an alias ("type X = int")
+	Package     string   // name of the declaring Go package
+	ImportPath  string   // import path of the declaring package, or its directory outside of a module
+	Doc         string   // text of the doc comment of the type, without directives
 }
 
 // GoFileData contains parsed Go file information.
 type GoFileData struct {
-	Structs []GoStruct
-	Aliases []TypeAlias
+	Structs    []GoStruct
+	Aliases    []TypeAlias
+	Interfaces []GoInterface
+	Enums      []GoEnum // declared types with constants, in declaration order
 }
 
 // StructInfo contains information about a Go struct.
@@ -44,90 +70,484 @@ type StructInfo struct {
 	Name       string
 	TypeParams []string
 	Fields     []FieldInfo
+	Embeds     []string // types embedded without a JSON name, whose fields are promoted
 }
 
 // FieldInfo contains information about a struct field.
 type FieldInfo struct {
-	Name string
-	Type string
-	Tags string
+	Name       string
+	Type       string
+	Tags       string
+	Directives []string
+	Doc        string
+	Comment    string
 }
 
 var genericTypePattern = regexp.MustCompile(`[a-zA-Z0-9_]+\[.*\]`)
 
+// instantiationPattern matches a whole generic instantiation, e.g. "pkg.Result[T, E]".
+var instantiationPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*\[.*\]$`)
+
+// ParseGoFilesOptions controls which declarations ParseGoFilesWithOptions extracts.
+type ParseGoFilesOptions struct {
+	// VarStructs also extracts the anonymous struct types of top-level var and
+	// const declarations as named structs, e.g. "var config = struct{...}{}" → "Config".
+	// A var struct whose name is taken by a declared type, or by an earlier
+	// var struct, is left out and recorded in Diagnostics.
+	VarStructs bool
+
+	// LocalTypes also extracts the struct types declared inside function
+	// bodies. A local type named like a top-level type is left out, and local
+	// types of the same name declared differently are an error.
+	LocalTypes bool
+
+	// AllDirs also walks the subdirectories skipped by default: vendor,
+	// node_modules, testdata and those whose name starts with a dot.
+	AllDirs bool
+
+	// NonRecursive only parses the .go files directly in the scanned
+	// directory, leaving out every subdirectory.
+	NonRecursive bool
+
+	// Diagnostics, when set, collects the declarations left out because of
+	// a name clash.
+	Diagnostics *Report
+}
+
+// skippedDir reports whether a subdirectory named name holds no source of
+// the scanned packages, such as vendored dependencies.
+func skippedDir(name string) bool {
+	switch name {
+	case "vendor", "node_modules", "testdata":
+		return true
+	}
+	return strings.HasPrefix(name, ".")
+}
+
 // ParseGoFiles recursively parses all .go files (except *_test.go) under the given directory.
 // It extracts struct and type alias definitions along with generic type parameters.
 func ParseGoFiles(dir string) (GoFileData, error) {
+	return ParseGoFilesWithOptions(dir, ParseGoFilesOptions{})
+}
+
+// ParseGoFilesWithOptions parses Go files under dir like ParseGoFiles, using opts.
+func ParseGoFilesWithOptions(dir string, opts ParseGoFilesOptions) (GoFileData, error) {
 	var data GoFileData
 	fset := token.NewFileSet()
+	methods := map[string][]string{}
+	marshalers := map[string]bool{}
+	enumConsts := map[string][]EnumMember{}
+	var enumOrder []string
+	var vars []varDecl
+	var locals []funcBody
+	importPaths := importPathCache{}
 
-	err := filepath.Walk(dir, func(path string, _ os.FileInfo, _ error) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, _ error) error {
+		if info != nil && info.IsDir() && path != dir && (opts.NonRecursive || !opts.AllDirs && skippedDir(info.Name())) {
+			return filepath.SkipDir
+		}
 		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
 			return nil
 		}
 
-		node, parseErr := parser.ParseFile(fset, path, nil, parser.AllErrors)
+		node, parseErr := parser.ParseFile(fset, path, nil, parser.AllErrors|parser.ParseComments)
 
 		if parseErr != nil {
 			return parseErr
 		}
 
+		pkg := goPackage{node.Name.Name, importPaths.of(filepath.Dir(path))}
 		for _, decl := range node.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+				if recv := receiverTypeName(funcDecl); recv != "" {
+					methods[recv] = append(methods[recv], funcDecl.Name.Name)
+					if isMarshalJSON(funcDecl) {
+						marshalers[recv] = true
+					}
+				}
+				if opts.LocalTypes && funcDecl.Body != nil {
+					locals = append(locals, funcBody{pkg, funcDecl.Body})
+				}
 				continue
 			}
 
-			for _, spec := range genDecl.Specs {
-				typeSpec := spec.(*ast.TypeSpec)
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
 
-				var typeParams []string
-				if typeSpec.TypeParams != nil {
-					for _, field := range typeSpec.TypeParams.List {
-						for _, name := range field.Names {
-							typeParams = append(typeParams, name.Name)
-						}
+			switch genDecl.Tok {
+			case token.TYPE:
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil && len(genDecl.Specs) == 1 {
+						doc = genDecl.Doc // "// X is..." above "type X struct"
 					}
+					collectTypeSpec(fset, pkg, typeSpec, doc, &data)
+				}
+			case token.VAR, token.CONST:
+				if opts.VarStructs {
+					vars = append(vars, varDecl{pkg, genDecl})
+				}
+				if genDecl.Tok == token.CONST {
+					collectEnumConsts(genDecl, enumConsts, &enumOrder)
+				}
+			}
+		}
+		return nil
+	})
+	// var structs and local types come second, so that a declared type wins
+	// wherever it is declared
+	if err == nil && len(vars) > 0 {
+		collectVarStructs(fset, vars, &data, opts.Diagnostics)
+	}
+	if err == nil && len(locals) > 0 {
+		err = collectLocalStructs(fset, locals, &data)
+	}
+
+	for i := range data.Structs {
+		data.Structs[i].Methods = methods[data.Structs[i].Name]
+		data.Structs[i].Marshaler = marshalers[data.Structs[i].Name]
+	}
+	data.Enums = enumsOf(data.Aliases, enumConsts, enumOrder)
+
+	return data, err
+}
+
+// collectTypeSpec adds the struct, interface or alias declared by typeSpec,
+// documented by doc, to data.
+func collectTypeSpec(fset *token.FileSet, pkg goPackage, typeSpec *ast.TypeSpec, doc *ast.CommentGroup, data *GoFileData) {
+	var typeParams, constraints []string
+	if typeSpec.TypeParams != nil {
+		for _, field := range typeSpec.TypeParams.List {
+			for _, name := range field.Names {
+				typeParams = append(typeParams, name.Name)
+				constraints = append(constraints, constraintString(field.Type))
+			}
+		}
+	}
+
+	// If it's a struct type, extract fields
+	if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+		data.Structs = append(data.Structs, GoStruct{
+			Name:        typeSpec.Name.Name,
+			Fields:      structFields(structType),
+			Embeds:      embeddedTypes(structType),
+			TypeParams:  typeParams,
+			Constraints: constraints,
+			Package:     pkg.name,
+			ImportPath:  pkg.path,
+			Doc:         strings.TrimSpace(doc.Text()),
+			Pos:         fset.Position(typeSpec.Pos()),
+		})
+		return
+	}
+
+	if ifaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+		if names := interfaceMethodNames(ifaceType); len(names) > 0 {
+			data.Interfaces = append(data.Interfaces, GoInterface{
+				Name:    typeSpec.Name.Name,
+				Methods: names,
+			})
+		}
+	}
+
+	// Otherwise treat as type alias with underlying type
+	underlying := ExprToString(typeSpec.Type)
+	data.Aliases = append(data.Aliases, TypeAlias{
+		Name:        typeSpec.Name.Name,
+		TypeParams:  typeParams,
+		Constraints: constraints,
+		Underlying:  underlying,
+		Defined:     !typeSpec.Assign.IsValid(),
+		Package:     pkg.name,
+		ImportPath:  pkg.path,
+		Doc:         strings.TrimSpace(doc.Text()),
+	})
+}
+
+// funcBody is the body of a function declared in package pkg.
+type funcBody struct {
+	pkg  goPackage
+	body *ast.BlockStmt
+}
+
+// collectLocalStructs adds the struct types declared anywhere in bodies,
+// including nested blocks and function literals, to data. Local types named
+// like a top-level type of data are left out, and so are repeated
+// declarations of a local type. Local types of the same name declared
+// differently are an error, as only one of them could be converted.
+func collectLocalStructs(fset *token.FileSet, bodies []funcBody, data *GoFileData) error {
+	topLevel := topLevelNames(data)
+
+	local := map[string]GoStruct{}
+	var err error
+	for _, fn := range bodies {
+		ast.Inspect(fn.body, func(n ast.Node) bool {
+			typeSpec, ok := n.(*ast.TypeSpec)
+			if !ok || err != nil {
+				return err == nil
+			}
+			if _, isStruct := typeSpec.Type.(*ast.StructType); !isStruct || topLevel[typeSpec.Name.Name] {
+				return true
+			}
+			var decl GoFileData
+			collectTypeSpec(fset, fn.pkg, typeSpec, typeSpec.Doc, &decl)
+			if len(decl.Structs) == 0 {
+				return true
+			}
+			s := decl.Structs[0]
+			if prev, seen := local[s.Name]; seen {
+				if !sameStruct(prev, s) {
+					err = fmt.Errorf("local struct %q is declared differently at %s and %s", s.Name, prev.Pos, s.Pos)
 				}
+				return true
+			}
+			local[s.Name] = s
+			data.Structs = append(data.Structs, s)
+			return true
+		})
+	}
+	return err
+}
+
+// varDecl is a var or const declaration of package pkg.
+type varDecl struct {
+	pkg  goPackage
+	decl *ast.GenDecl
+}
 
-				// If it's a struct type, extract fields
-				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-					var fields []StructField
-					for _, field := range structType.Fields.List {
-						fieldType := ExprToString(field.Type)
-						tag := ""
-						if field.Tag != nil {
-							tag = strings.Trim(field.Tag.Value, "`")
-						}
-						for _, name := range field.Names {
-							fields = append(fields, StructField{
-								Name: name.Name,
-								Type: fieldType,
-								Tags: tag,
-							})
-						}
+// collectVarStructs adds a named struct for every value spec of decls whose
+// type is an anonymous struct, either declared ("var x struct{...}") or
+// given by a composite literal ("var x = struct{...}{...}"). A struct whose
+// exported name is taken by a type of data or an earlier var struct is left
+// out and recorded in report.
+func collectVarStructs(fset *token.FileSet, decls []varDecl, data *GoFileData, report *Report) {
+	taken := topLevelNames(data)
+	for _, d := range decls {
+		for _, spec := range d.decl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				structType, ok := valueSpec.Type.(*ast.StructType)
+				if !ok && i < len(valueSpec.Values) {
+					if lit, isLit := valueSpec.Values[i].(*ast.CompositeLit); isLit {
+						structType, ok = lit.Type.(*ast.StructType)
 					}
-					data.Structs = append(data.Structs, GoStruct{
-						Name:       typeSpec.Name.Name,
-						Fields:     fields,
-						TypeParams: typeParams,
-					})
+				}
+				if !ok || name.Name == "_" {
 					continue
 				}
-
-				// Otherwise treat as type alias with underlying type
-				underlying := ExprToString(typeSpec.Type)
-				data.Aliases = append(data.Aliases, TypeAlias{
-					Name:       typeSpec.Name.Name,
-					TypeParams: typeParams,
-					Underlying: underlying,
+				typeName := exportedName(name.Name)
+				if taken[typeName] {
+					if report != nil {
+						report.add(KindLossy, name.Name, "var struct at %s left out: %s is already declared", fset.Position(name.Pos()), typeName)
+					}
+					continue
+				}
+				taken[typeName] = true
+				data.Structs = append(data.Structs, GoStruct{
+					Name:       typeName,
+					Fields:     structFields(structType),
+					Embeds:     embeddedTypes(structType),
+					Package:    d.pkg.name,
+					ImportPath: d.pkg.path,
+					Pos:        fset.Position(name.Pos()),
 				})
 			}
 		}
+	}
+}
+
+// topLevelNames returns the names of the structs, aliases and interfaces of data.
+func topLevelNames(data *GoFileData) map[string]bool {
+	names := map[string]bool{}
+	for _, s := range data.Structs {
+		names[s.Name] = true
+	}
+	for _, a := range data.Aliases {
+		names[a.Name] = true
+	}
+	for _, iface := range data.Interfaces {
+		names[iface.Name] = true
+	}
+	return names
+}
+
+// structFields extracts the named fields of a struct type.
+func structFields(structType *ast.StructType) []StructField {
+	var fields []StructField
+	for _, field := range structType.Fields.List {
+		fieldType := ExprToString(field.Type)
+		tag := ""
+		if field.Tag != nil {
+			tag = strings.Trim(field.Tag.Value, "`")
+		}
+		directives := ParseDirectives(field.Doc, field.Comment)
+		doc := strings.TrimSpace(field.Doc.Text())
+		comment := strings.TrimSpace(field.Comment.Text())
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		if len(field.Names) == 0 && jsonName(tag) != "" {
+			// an embedded field named by its tag is nested, not promoted
+			names = append(names, embeddedName(fieldType))
+		}
+		for _, name := range names {
+			fields = append(fields, StructField{
+				Name:       name,
+				Type:       fieldType,
+				Tags:       tag,
+				Directives: directives,
+				Doc:        doc,
+				Comment:    comment,
+			})
+		}
+	}
+	return fields
+}
+
+// jsonName returns the name given by the json key of a struct tag.
+func jsonName(tag string) string {
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	return name
+}
+
+// embeddedName returns the field name of an embedded type, e.g. "Base" for
+// "*pkg.Base[T]".
+func embeddedName(embedded string) string {
+	name := strings.TrimPrefix(embedded, "*")
+	name, _, _ = strings.Cut(name, "[")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// embeddedTypes returns the embedded fields of structType whose fields
+// encoding/json promotes into the parent, i.e. those without a JSON name.
+func embeddedTypes(structType *ast.StructType) []string {
+	var embeds []string
+	for _, field := range structType.Fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		if field.Tag != nil && jsonName(strings.Trim(field.Tag.Value, "`")) != "" {
+			continue
+		}
+		embeds = append(embeds, ExprToString(field.Type))
+	}
+	return embeds
+}
+
+// exportedName upper-cases the first letter of name, e.g. "config" → "Config".
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// DirectivePrefix marks go2ts comment directives, e.g. "//go2ts:nullable".
+const DirectivePrefix = "//go2ts:"
+
+// ParseDirectives returns the go2ts directives found in the comment groups,
+// without the prefix, in source order.
+func ParseDirectives(groups ...*ast.CommentGroup) []string {
+	var directives []string
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, c := range g.List {
+			if d, ok := strings.CutPrefix(c.Text, DirectivePrefix); ok {
+				if d = strings.TrimSpace(d); d != "" {
+					directives = append(directives, d)
+				}
+			}
+		}
+	}
+	return directives
+}
+
+// isMarshalJSON reports whether fn is a MarshalJSON method implementing
+// json.Marshaler: "MarshalJSON() ([]byte, error)".
+func isMarshalJSON(fn *ast.FuncDecl) bool {
+	if fn.Name.Name != "MarshalJSON" || fn.Type.Params.NumFields() != 0 || fn.Type.Results.NumFields() != 2 {
+		return false
+	}
+	results := fn.Type.Results.List
+	return ExprToString(results[0].Type) == "[]byte" && ExprToString(results[len(results)-1].Type) == "error"
+}
+
+// receiverTypeName returns the base type name of a method receiver,
+// e.g. "Foo" for "func (f *Foo[T]) Bar()". It returns "" for plain functions.
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// interfaceMethodNames returns the names of the methods declared directly in an interface.
+func interfaceMethodNames(iface *ast.InterfaceType) []string {
+	if iface.Methods == nil {
 		return nil
-	})
+	}
+	var names []string
+	for _, m := range iface.Methods.List {
+		if _, ok := m.Type.(*ast.FuncType); !ok {
+			continue
+		}
+		for _, n := range m.Names {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
 
-	return data, err
+// constraintString returns the string form of a type parameter constraint,
+// with an inline union such as ~int | ~float64 written as the interface it
+// abbreviates, interface{ ~int | ~float64 }.
+func constraintString(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.UnaryExpr, *ast.BinaryExpr:
+		return typeSetPrefix + ExprToString(expr) + " }"
+	}
+	return ExprToString(expr)
+}
+
+// typeSetTerms returns the type set of a constraint interface such as
+// interface{ ~int | ~float64 }, or "" when iface declares no unions or
+// approximations.
+func typeSetTerms(iface *ast.InterfaceType) string {
+	if iface.Methods == nil {
+		return ""
+	}
+	for _, m := range iface.Methods.List {
+		switch m.Type.(type) {
+		case *ast.UnaryExpr, *ast.BinaryExpr:
+			return ExprToString(m.Type)
+		}
+	}
+	return ""
 }
 
 // ExprToString converts a Go AST expression to its string representation.
@@ -140,7 +560,20 @@ func ExprToString(expr ast.Expr) string {
 	case *ast.SelectorExpr:
 		return ExprToString(t.X) + "." + t.Sel.Name
 	case *ast.ArrayType:
+		if t.Len != nil {
+			return "[" + ExprToString(t.Len) + "]" + ExprToString(t.Elt)
+		}
 		return "[]" + ExprToString(t.Elt)
+	case *ast.BasicLit:
+		return t.Value
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.RECV:
+			return "<-chan " + ExprToString(t.Value)
+		case ast.SEND:
+			return "chan<- " + ExprToString(t.Value)
+		}
+		return "chan " + ExprToString(t.Value)
 	case *ast.MapType:
 		return "map[" + ExprToString(t.Key) + "]" + ExprToString(t.Value)
 	case *ast.IndexExpr:
@@ -151,10 +584,25 @@ func ExprToString(expr ast.Expr) string {
 			indexes = append(indexes, ExprToString(idx))
 		}
 		return ExprToString(t.X) + "[" + strings.Join(indexes, ", ") + "]"
+	case *ast.UnaryExpr:
+		if t.Op != token.TILDE {
+			return ""
+		}
+		return "~" + ExprToString(t.X)
+	case *ast.BinaryExpr:
+		if t.Op != token.OR {
+			return ""
+		}
+		return ExprToString(t.X) + " | " + ExprToString(t.Y)
 	case *ast.InterfaceType:
+		if terms := typeSetTerms(t); terms != "" {
+			return "interface{ " + terms + " }"
+		}
 		return "interface{}"
 	case *ast.FuncType:
-		return "func"
+		return funcTypeString(t)
+	case *ast.Ellipsis:
+		return "..." + ExprToString(t.Elt)
 	case *ast.StructType:
 		if t == nil || t.Fields == nil || len(t.Fields.List) == 0 {
 			return "struct{}"
@@ -181,6 +629,113 @@ func ExprToString(expr ast.Expr) string {
 	}
 }
 
+// Config controls how Go types are converted to TypeScript.
+// A nil *Config uses the default mappings.
+type Config struct {
+	// Overrides maps full Go type strings (e.g. "primitive.DateTime") to
+	// TypeScript types. Overrides take precedence over the built-in mappings.
+	Overrides map[string]string
+
+	// Report, when set, collects diagnostics for lossy conversions such as
+	// fallbacks to any or coerced map keys.
+	Report *Report
+
+	// URLAsObject maps url.URL and *url.URL to the object encoding/json
+	// produces for them (url.URL has no JSON or text marshaler) instead of string.
+	URLAsObject bool
+
+	// KeepNames lists declared type names that are referenced by name instead
+	// of being expanded to their underlying type, e.g. interfaces emitted as unions.
+	KeepNames map[string]bool
+
+	// PreserveNamedAliases references declared type aliases and defined types,
+	// e.g. Email for "type Email string", by name instead of expanding them to
+	// their underlying type. The generator declares each of them as a type alias.
+	PreserveNamedAliases bool
+
+	// ReadonlyArrays emits every slice as "readonly T[]".
+	ReadonlyArrays bool
+
+	// RuneAsString maps rune to a single-character string instead of number.
+	RuneAsString bool
+
+	// ErrorAsString maps error to string instead of Error, for APIs that
+	// marshal errors as their message.
+	ErrorAsString bool
+
+	// TimeAsDate maps time.Time to Date instead of string, for clients
+	// reviving the RFC 3339 timestamps encoding/json writes into Dates.
+	TimeAsDate bool
+
+	// RuneSliceAsString maps []rune to string. []int32 keeps mapping to
+	// number[] and []byte keeps its own mapping.
+	RuneSliceAsString bool
+
+	// Trace, when set, records every recursive conversion with its input and
+	// output, for debugging how a type was resolved.
+	Trace *Trace
+
+	// Sets controls how map[K]struct{}, Go's set idiom, is emitted.
+	Sets SetStyle
+
+	// JSONAccurateMapKeys emits the keys of maps with integer keys as string,
+	// the type JSON object keys always have at runtime. By default they are
+	// number, which indexes conveniently but hides that Object.keys and
+	// for...in yield strings, e.g. "1" for map[int]T.
+	JSONAccurateMapKeys bool
+
+	// ByteArraysAsNumbers maps fixed byte arrays such as [32]byte to
+	// number[], what encoding/json writes for a bare array. By default they
+	// map to string, as the hash and key types built on them usually
+	// marshal themselves as hex or base64 text.
+	ByteArraysAsNumbers bool
+}
+
+// SetStyle selects the TypeScript form of map[K]struct{}.
+type SetStyle int
+
+const (
+	// SetAsMap keeps the plain index signature, { [key: K]: any }.
+	SetAsMap SetStyle = iota
+	// SetAsSet emits Set<K>.
+	SetAsSet
+	// SetAsRecord emits { [key: K]: true }.
+	SetAsRecord
+)
+
+func (c *Config) setStyle() SetStyle {
+	if c == nil {
+		return SetAsMap
+	}
+	return c.Sets
+}
+
+func (c *Config) keepName(goType string) bool {
+	return c != nil && c.KeepNames[goType]
+}
+
+// keepAlias reports whether references to the alias name are kept rather
+// than expanded through aliasMap.
+func (c *Config) keepAlias(name string, aliasMap map[string]string) bool {
+	if c.keepName(name) {
+		return true
+	}
+	_, ok := aliasMap[name]
+	return ok && c != nil && c.PreserveNamedAliases
+}
+
+// URLObjectType is the TypeScript shape of url.URL as written by encoding/json.
+const URLObjectType = "{ Scheme: string; Opaque: string; User: any; Host: string; Path: string; " +
+	"RawPath: string; OmitHost: boolean; ForceQuery: boolean; RawQuery: string; Fragment: string; RawFragment: string }"
+
+func (c *Config) override(goType string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	ts, ok := c.Overrides[goType]
+	return ts, ok
+}
+
 // GoTypeToTSType converts a Go type string into a corresponding TypeScript type.
 func GoTypeToTSType(
 	goType string,
@@ -189,17 +744,47 @@ func GoTypeToTSType(
 	structMap map[string]StructInfo,
 	typeParamMapping map[string]string,
 	visited map[string]bool,
+) string {
+	return GoTypeToTSTypeWithConfig(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, nil)
+}
+
+// GoTypeToTSTypeWithConfig converts a Go type string into a TypeScript type using cfg.
+func GoTypeToTSTypeWithConfig(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
+) string {
+	if cfg == nil || cfg.Trace == nil {
+		return goTypeToTSType(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+	}
+	return cfg.traced(goType, func() string {
+		return goTypeToTSType(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+	})
+}
+
+func goTypeToTSType(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
 ) string {
 	goType = strings.TrimSpace(goType)
 
+	// visited holds the alias names currently being expanded; structural
+	// recursion (pointers, slices, maps, generic args) never marks it, so
+	// nested instantiations like A[A[int]] are not mistaken for cycles.
 	if visited[goType] {
-		return "any" // circular reference prevention
+		cfg.report(goType, "circular alias reference converted to any")
+		return "any" // circular alias reference prevention
 	}
 
-	visited[goType] = true
-	defer delete(visited, goType)
-
-	// Preventing circular references
 	if mapped, ok := typeParamMapping[goType]; ok {
 		return mapped
 	}
@@ -208,10 +793,43 @@ func GoTypeToTSType(
 		return ""
 	}
 
-	if special := checkSpecialCases(goType); special != "" {
+	if override, ok := cfg.override(goType); ok {
+		return override
+	}
+
+	if cfg != nil && cfg.URLAsObject && goType == "url.URL" {
+		return URLObjectType
+	}
+
+	if cfg != nil && cfg.RuneAsString && goType == "rune" {
+		return "string"
+	}
+
+	if cfg != nil && cfg.ErrorAsString && goType == "error" {
+		return "string"
+	}
+
+	if cfg != nil && cfg.TimeAsDate && goType == "time.Time" {
+		return "Date"
+	}
+
+	if special := checkSpecialCases(goType, cfg); special != "" {
 		return special
 	}
 
+	// an approximation ~T converts like T
+	if strings.HasPrefix(goType, "~") {
+		return GoTypeToTSTypeWithConfig(goType[len("~"):], aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+	}
+
+	if isInlineInterface(goType) {
+		if strings.HasPrefix(goType, typeSetPrefix) && strings.HasSuffix(goType, " }") && !strings.Contains(goType, "(") {
+			return typeSetType(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		}
+		cfg.report(goType, "interface with methods converted to any")
+		return "any"
+	}
+
 	// return generic type params
 	for _, tp := range typeParams {
 		if goType == tp {
@@ -223,43 +841,88 @@ func GoTypeToTSType(
 	const slicePrefix = len("[]")
 
 	if strings.HasPrefix(goType, "*") {
-		inner := GoTypeToTSType(goType[ptrPrefix:], aliasMap, typeParams, structMap, typeParamMapping, visited)
+		inner := GoTypeToTSTypeWithConfig(goType[ptrPrefix:], aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		inner = cfg.Resolved(inner, goType[ptrPrefix:])
+		if hasTopLevelArrow(inner) {
+			inner = "(" + inner + ")"
+		} else if strings.HasSuffix(inner, " | null") { // **T is null only once
+			return inner
+		}
 		return inner + " | null"
 	}
 
 	if strings.HasPrefix(goType, "[]") {
-		elem := GoTypeToTSType(goType[slicePrefix:], aliasMap, typeParams, structMap, typeParamMapping, visited)
-		if strings.HasPrefix(elem, "{ [key:") && !strings.HasPrefix(elem, "(") {
+		elem := GoTypeToTSTypeWithConfig(goType[slicePrefix:], aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		elem = cfg.Resolved(elem, goType[slicePrefix:])
+		if strings.HasPrefix(elem, "{ [key:") || hasTopLevelUnion(elem) || hasTopLevelArrow(elem) {
 			elem = "(" + elem + ")"
 		}
+		if cfg != nil && cfg.ReadonlyArrays {
+			if strings.HasPrefix(elem, "readonly ") {
+				elem = "(" + elem + ")"
+			}
+			return "readonly " + elem + "[]"
+		}
 		return elem + "[]"
 	}
 
+	if elem, ok := chanElem(goType); ok {
+		elem := GoTypeToTSTypeWithConfig(elem, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		return "AsyncIterable<" + elem + ">"
+	}
+
+	if elem, ok := fixedArrayElem(goType); ok {
+		if elem == "byte" || elem == "uint8" {
+			if cfg != nil && cfg.ByteArraysAsNumbers {
+				return "number[]"
+			}
+			return "string"
+		}
+		return GoTypeToTSTypeWithConfig("[]"+elem, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+	}
+
 	if strings.HasPrefix(goType, "map[") {
 		return parseMapType(goType,
 			aliasMap,
 			typeParams,
 			structMap,
 			typeParamMapping,
-			visited)
+			visited,
+			cfg)
+	}
+
+	if strings.HasPrefix(goType, "func(") {
+		return funcType(goType,
+			aliasMap,
+			typeParams,
+			structMap,
+			typeParamMapping,
+			visited,
+			cfg)
 	}
 
 	if strings.HasPrefix(goType, "struct{") {
-		return ParseStructType(goType,
+		return parseStructType(goType,
 			aliasMap,
 			typeParams,
 			structMap,
 			typeParamMapping,
-			visited)
+			visited,
+			cfg)
 	}
 
 	if genericTypePattern.MatchString(goType) {
-		return CheckGenericPatterns(goType,
+		return checkGenericPatterns(goType,
 			aliasMap,
 			typeParams,
 			structMap,
 			typeParamMapping,
-			visited)
+			visited,
+			cfg)
+	}
+
+	if cfg.keepName(goType) {
+		return goType
 	}
 
 	if aliasResult := checkAliasTypes(goType,
@@ -267,7 +930,8 @@ func GoTypeToTSType(
 		typeParams,
 		structMap,
 		typeParamMapping,
-		visited); aliasResult != "" {
+		visited,
+		cfg); aliasResult != "" {
 		return aliasResult
 	}
 
@@ -276,6 +940,7 @@ func GoTypeToTSType(
 	}
 
 	if complexResult := checkComplexTypes(goType); complexResult != "" {
+		cfg.report(goType, "unsupported type converted to %s", complexResult)
 		return complexResult
 	}
 
@@ -283,25 +948,111 @@ func GoTypeToTSType(
 		if IsUserDefinedStruct(goType, structMap) {
 			return goType
 		}
+		cfg.reportUnresolved(goType, goType)
 		return goType
 	}
 	return goType
 }
 
-func checkSpecialCases(goType string) string {
+const typeSetPrefix = "interface{ "
+
+// typeSetType converts a type set, e.g. "interface{ ~int | ~string }", to the
+// union of its converted terms with duplicates removed ("number | string").
+func typeSetType(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
+) string {
+	terms := strings.Split(strings.TrimSuffix(strings.TrimPrefix(goType, typeSetPrefix), " }"), " | ")
+	var union []string
+	for _, term := range terms {
+		ts := GoTypeToTSTypeWithConfig(term, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		ts = cfg.Resolved(ts, term)
+		if !slices.Contains(union, ts) {
+			union = append(union, ts)
+		}
+	}
+	return strings.Join(union, " | ")
+}
+
+// isInlineInterface reports whether goType is an interface type literal
+// other than the empty interface, e.g. "interface{ String() string }".
+func isInlineInterface(goType string) bool {
+	switch goType {
+	case "interface{}", "interface {}":
+		return false
+	}
+	return strings.HasPrefix(goType, "interface{") || strings.HasPrefix(goType, "interface {")
+}
+
+// chanElem returns the element type of a channel type, e.g. "Job" for
+// "chan Job" or "<-chan Job". The values received from a channel convert
+// to an async iterable of its elements.
+func chanElem(goType string) (string, bool) {
+	for _, prefix := range []string{"chan<- ", "<-chan ", "chan "} {
+		if elem, ok := strings.CutPrefix(goType, prefix); ok {
+			return strings.TrimSpace(elem), true
+		}
+	}
+	return "", false
+}
+
+// fixedArrayElem returns the element type of a fixed-length array type such
+// as [32]byte; other arrays convert like slices of it.
+func fixedArrayElem(goType string) (string, bool) {
+	if !strings.HasPrefix(goType, "[") || strings.HasPrefix(goType, "[]") {
+		return "", false
+	}
+	end := strings.Index(goType, "]")
+	if end < 0 {
+		return "", false
+	}
+	return goType[end+1:], true
+}
+
+func checkSpecialCases(goType string, cfg *Config) string {
 	switch goType {
 	case "[]byte":
 		return "Uint8Array"
+	case "[]rune":
+		if cfg != nil && cfg.RuneSliceAsString {
+			return "string"
+		}
 	case "struct{}":
 		return "any"
 	case "func":
 		return "(...args: any[]) => any"
-	case "*time.Time", "*url.URL":
-		return "string"
 	}
 	return ""
 }
 
+// hasTopLevelUnion reports whether ts is a union outside of any grouping,
+// e.g. "string | null" but not "Result<string | null>".
+func hasTopLevelUnion(ts string) bool {
+	depth := 0
+	for i, r := range ts {
+		switch r {
+		case '(', '{', '<', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case '>':
+			if i == 0 || ts[i-1] != '=' { // not the arrow of a function type
+				depth--
+			}
+		case '|':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsUserDefinedStruct checks whether the type name is a user-defined struct.
 func IsUserDefinedStruct(name string, structMap map[string]StructInfo) bool {
 	_, ok := structMap[name]
@@ -374,6 +1125,8 @@ func SplitGenericType(goType string) (base string, params []string) {
 }
 
 // CheckGenericPatterns - Converts a Go generic type to a TypeScript generic type, handling params and aliases.
+// Types that are not an instantiation themselves, such as an alias of one
+// (AliasMapResultType = GenericResult[AliasMapType]), are converted like any other reference.
 func CheckGenericPatterns(
 	goType string,
 	aliasMap map[string]string,
@@ -381,33 +1134,77 @@ func CheckGenericPatterns(
 	structMap map[string]StructInfo,
 	typeParamMapping map[string]string,
 	visited map[string]bool,
+) string {
+	if !instantiationPattern.MatchString(goType) || strings.HasPrefix(goType, "map[") {
+		return GoTypeToTSType(goType, aliasMap, typeParams, structMap, typeParamMapping, visited)
+	}
+	return checkGenericPatterns(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, nil)
+}
+
+func checkGenericPatterns(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
 ) string {
 	// Split base type and type parameters (e.g., "Result[T, E]" → base:"Result", params:["T","E"]
 	base, params := SplitGenericType(goType)
+	if strings.TrimSpace(strings.Join(params, "")) == "" {
+		cfg.report(goType, "malformed generic type converted to any")
+		return "any"
+	}
+
+	// sql.Null[T] is T or NULL, like sql.NullString
+	if base == "sql.Null" && len(params) == 1 {
+		return GoTypeToTSTypeWithConfig("*"+params[0], aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+	}
+
+	// A generic from another package has no generated declaration to refer to
+	if _, isAlias := aliasMap[base]; !isAlias && strings.Contains(base, ".") {
+		cfg.report(goType, "generic type from unscanned package converted to any")
+		return "any"
+	}
+
+	// Generic structs must be instantiated with as many arguments as they declare
+	if info, ok := structMap[base]; ok && len(info.TypeParams) != len(params) {
+		cfg.report(goType, "generic type %s expects %d type arguments, got %d", base, len(info.TypeParams), len(params))
+	} else if _, isAlias := aliasMap[base]; !ok && !isAlias && IsAliasName(base) {
+		cfg.reportUnresolved(goType, base)
+	}
 
 	// Recursively convert all type parameters into TypeScript types
 	tsParams := make([]string, 0, len(params))
 	for _, p := range params {
-		tsParam := GoTypeToTSType(
+		tsParam := GoTypeToTSTypeWithConfig(
 			p,
 			aliasMap,
 			typeParams,
 			structMap,
 			typeParamMapping,
 			visited,
+			cfg,
 		)
-		tsParams = append(tsParams, tsParam)
+		tsParams = append(tsParams, cfg.Resolved(tsParam, p))
 	}
 
-	// If base type has an alias mapping, replace it (e.g., "int" → "number")
-	if baseAlias, ok := aliasMap[base]; ok && baseAlias != base {
-		base = GoTypeToTSType(
+	// If base type has an alias mapping, replace it (e.g., "int" → "number").
+	// A generic alias of a composite type, e.g. Forest[T] = []Tree[T], is
+	// declared with its own type parameters and referenced by name instead.
+	if baseAlias, ok := aliasMap[base]; ok && baseAlias != base && !visited[base] && !cfg.keepAlias(base, aliasMap) &&
+		!strings.ContainsAny(baseAlias, "[]*{ ") {
+		visited[base] = true
+		defer delete(visited, base)
+		base = GoTypeToTSTypeWithConfig(
 			baseAlias,
 			aliasMap,
 			typeParams,
 			structMap,
 			typeParamMapping,
 			visited,
+			cfg,
 		)
 	}
 
@@ -416,6 +1213,10 @@ func CheckGenericPatterns(
 }
 
 func checkBasicTypes(goType string) string {
+	if ts, ok := knownType(goType); ok {
+		return ts
+	}
+
 	switch goType {
 	case "string":
 		return "string"
@@ -430,23 +1231,21 @@ func checkBasicTypes(goType string) string {
 		return "string"
 	case "url.URL":
 		return "string"
-	case "interface{}", "*interface{}", "interface {}", "*interface {}":
+	case "interface{}", "*interface{}", "interface {}", "*interface {}", "any":
 		return "any"
 	case "complex64", "complex128":
 		return "any"
-	case "decimal.Decimal", "primitive.ObjectID", "primitive.Decimal128",
-		"uuid.UUID", "pgtype.UUID":
+	case "big.Float", "big.Rat":
+		// text marshalers, e.g. "1.5" and "1/3"
 		return "string"
-	case "sql.NullString":
-		return "string | null"
-	case "sql.NullInt64":
-		return "number | null"
-	case "pq.NullTime":
-		return "string | null"
-	case "sql.NullBool":
-		return "boolean | null"
+	case "big.Int":
+		// big.Int implements json.Marshaler and writes a bare JSON number
+		return "number"
 	case "unsafe.Pointer":
 		return "any"
+	case "json.RawMessage":
+		// raw JSON passed through undecoded, of any shape
+		return "unknown"
 	case "error":
 		return "Error"
 	}
@@ -459,12 +1258,23 @@ func checkAliasTypes(goType string,
 	typeParams []string,
 	structMap map[string]StructInfo,
 	typeParamMapping map[string]string,
-	visited map[string]bool) string {
+	visited map[string]bool,
+	cfg *Config) string {
+	// An alias is expanded through its underlying type, so an alias of a
+	// generic instantiation over further aliases resolves fully, e.g.
+	// AliasMapResultType → GenericResult[AliasMapType] → GenericResult<{ [key: string]: string }>.
+	// Names in Config.KeepNames are kept before this point.
 	if base, ok := aliasMap[goType]; ok {
 		if base == goType {
+			cfg.report(goType, "self-referencing alias converted to any")
 			return "any"
 		}
-		return GoTypeToTSType(base, aliasMap, typeParams, structMap, typeParamMapping, visited)
+		if cfg.keepAlias(goType, aliasMap) {
+			return goType
+		}
+		visited[goType] = true
+		defer delete(visited, goType)
+		return GoTypeToTSTypeWithConfig(base, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
 	}
 	return ""
 }
@@ -486,12 +1296,14 @@ func parseMapType(
 	structMap map[string]StructInfo,
 	typeParamMapping map[string]string,
 	visited map[string]bool,
+	cfg *Config,
 ) string {
 	const mapTypeSplitLimit = 2
 
 	inner := goType[len("map["):]
 	parts := strings.SplitN(inner, "]", mapTypeSplitLimit)
 	if len(parts) != mapTypeSplitLimit {
+		cfg.report(goType, "malformed map type converted to any")
 		return "any"
 	}
 	rawKey := strings.TrimSpace(parts[0])
@@ -499,6 +1311,7 @@ func parseMapType(
 
 	var keyTS string
 	if strings.HasPrefix(rawKey, "struct{") {
+		cfg.report(goType, "map key %s coerced to string", rawKey)
 		keyTS = "string"
 	} else {
 		switch rawKey {
@@ -519,26 +1332,52 @@ func parseMapType(
 					break
 				}
 			}
-			keyTS = GoTypeToTSType(keyResolved, aliasMap, typeParams, structMap, typeParamMapping, visited)
+			keyTS = GoTypeToTSTypeWithConfig(keyResolved, aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
 			if keyTS != "string" && keyTS != "number" && keyTS != "symbol" {
+				cfg.report(goType, "map key %s coerced to string", rawKey)
 				keyTS = "string"
 			}
 		}
 	}
 
-	valTS := GoTypeToTSType(rawVal,
+	if keyTS == "number" && cfg != nil && cfg.JSONAccurateMapKeys {
+		keyTS = "string"
+	}
+
+	if rawVal == "struct{}" {
+		if ts, ok := setType(keyTS, cfg); ok {
+			return ts
+		}
+	}
+
+	valTS := GoTypeToTSTypeWithConfig(rawVal,
 		aliasMap,
 		typeParams,
 		structMap,
 		typeParamMapping,
-		visited)
+		visited,
+		cfg)
+	valTS = cfg.Resolved(valTS, rawVal)
 
-	if strings.Contains(valTS, "|") && !strings.HasSuffix(valTS, "[]") && !strings.HasPrefix(valTS, "(") {
+	if hasTopLevelUnion(valTS) {
 		valTS = "(" + valTS + ")"
 	}
 	return "{ [key: " + keyTS + "]: " + valTS + " }"
 }
 
+// setType returns the TypeScript form of a map[K]struct{} set, unless sets
+// are left as plain maps.
+func setType(keyTS string, cfg *Config) (string, bool) {
+	switch cfg.setStyle() {
+	case SetAsSet:
+		return "Set<" + keyTS + ">", true
+	case SetAsRecord:
+		return "{ [key: " + keyTS + "]: true }", true
+	default:
+		return "", false
+	}
+}
+
 const minFieldParts = 2 // name, type
 
 // ParseStructType converts an inline Go anonymous struct into a TypeScript object type string.
@@ -550,24 +1389,66 @@ func ParseStructType(
 	structMap map[string]StructInfo,
 	typeParamMapping map[string]string,
 	visited map[string]bool,
+) string {
+	return parseStructType(goType, aliasMap, typeParams, structMap, typeParamMapping, visited, nil)
+}
+
+func parseStructType(
+	goType string,
+	aliasMap map[string]string,
+	typeParams []string,
+	structMap map[string]StructInfo,
+	typeParamMapping map[string]string,
+	visited map[string]bool,
+	cfg *Config,
 ) string {
 	body := strings.TrimPrefix(goType, "struct{")
 	body = strings.TrimSuffix(body, "}")
-	fields := strings.Split(body, ";")
 	var tsFields []string
-	for _, f := range fields {
+	for _, f := range splitStructFields(body) {
 		f = strings.TrimSpace(f)
 		if f == "" {
 			continue
 		}
 		parts := strings.Fields(f)
-		if len(parts) >= minFieldParts {
-			tsFields = append(tsFields, fmt.Sprintf("%s: %s",
-				parts[0],
-				GoTypeToTSType(strings.Join(parts[1:], " "), aliasMap, typeParams, structMap, typeParamMapping, visited)))
-		} else {
+		if len(parts) < minFieldParts {
 			tsFields = append(tsFields, "unknown: any")
+			continue
+		}
+		// "A, B int" declares several fields of one type
+		names := 1
+		for names < len(parts) && strings.HasSuffix(parts[names-1], ",") {
+			names++
+		}
+		if names >= len(parts) {
+			tsFields = append(tsFields, "unknown: any")
+			continue
+		}
+		ts := GoTypeToTSTypeWithConfig(strings.Join(parts[names:], " "), aliasMap, typeParams, structMap, typeParamMapping, visited, cfg)
+		for _, name := range parts[:names] {
+			tsFields = append(tsFields, fmt.Sprintf("%s: %s", strings.TrimSuffix(name, ","), ts))
 		}
 	}
 	return "{ " + strings.Join(tsFields, "; ") + " }"
 }
+
+// splitStructFields splits the body of an inline struct type at the
+// semicolons that are not inside a nested struct.
+func splitStructFields(body string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range body {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ';':
+			if depth == 0 {
+				fields = append(fields, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(fields, body[start:])
+}
@@ -2,15 +2,34 @@ package parser_test
 
 import (
 	"go/ast"
+	"go/token"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/limbicnode/go2ts/internal/parser"
 )
 
+// writeTree writes files, keyed by slash-separated path, to a new temporary
+// directory and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
 func TestParseGoFiles_EdgeCases(t *testing.T) {
 	dir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(dir, "skip_test.go"), []byte("package main"), 0644); err != nil {
@@ -128,13 +147,25 @@ func TestExprToString(t *testing.T) {
 		{"StarExpr", &ast.StarExpr{X: &ast.Ident{Name: "MyType"}}, "*MyType"},
 		{"SelectorExpr", &ast.SelectorExpr{X: &ast.Ident{Name: "pkg"}, Sel: &ast.Ident{Name: "Type"}}, "pkg.Type"},
 		{"ArrayType", &ast.ArrayType{Elt: &ast.Ident{Name: "int"}}, "[]int"},
+		{"FixedArrayType", &ast.ArrayType{Len: &ast.BasicLit{Kind: token.INT, Value: "32"}, Elt: &ast.Ident{Name: "byte"}}, "[32]byte"},
 		{"MapType", &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}}, "map[string]int"},
 		{"IndexExpr", &ast.IndexExpr{X: &ast.Ident{Name: "MyType"}, Index: &ast.Ident{Name: "T"}}, "MyType[T]"},
 		{"IndexListExpr", &ast.IndexListExpr{
 			X:       &ast.Ident{Name: "MyType"},
 			Indices: []ast.Expr{&ast.Ident{Name: "T"}, &ast.Ident{Name: "K"}},
 		}, "MyType[T, K]"},
+		{"ChanType", &ast.ChanType{Dir: ast.SEND | ast.RECV, Value: &ast.Ident{Name: "Job"}}, "chan Job"},
+		{"RecvChanType", &ast.ChanType{Dir: ast.RECV, Value: &ast.Ident{Name: "string"}}, "<-chan string"},
+		{"SendChanType", &ast.ChanType{Dir: ast.SEND, Value: &ast.StarExpr{X: &ast.Ident{Name: "Job"}}}, "chan<- *Job"},
 		{"InterfaceType", &ast.InterfaceType{}, "interface{}"},
+		{"Approximation", &ast.UnaryExpr{Op: token.TILDE, X: &ast.Ident{Name: "int"}}, "~int"},
+		{"TypeSetInterface", &ast.InterfaceType{Methods: &ast.FieldList{List: []*ast.Field{{
+			Type: &ast.BinaryExpr{
+				X:  &ast.UnaryExpr{Op: token.TILDE, X: &ast.Ident{Name: "int"}},
+				Op: token.OR,
+				Y:  &ast.Ident{Name: "string"},
+			},
+		}}}}, "interface{ ~int | string }"},
 		{"EmptyStructType", &ast.StructType{}, "struct{}"},
 		{"StructWithFields", &ast.StructType{
 			Fields: &ast.FieldList{
@@ -152,6 +183,16 @@ func TestExprToString(t *testing.T) {
 			},
 		}, "struct{ MyEmbeddedType }"},
 		{"FuncType", &ast.FuncType{}, "func"},
+		{"FuncSignature", &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{
+				{Names: []*ast.Ident{{Name: "x"}, {Name: "y"}}, Type: &ast.Ident{Name: "int"}},
+				{Names: []*ast.Ident{{Name: "opts"}}, Type: &ast.Ellipsis{Elt: &ast.Ident{Name: "string"}}},
+			}},
+			Results: &ast.FieldList{List: []*ast.Field{
+				{Type: &ast.Ident{Name: "int"}},
+				{Type: &ast.Ident{Name: "error"}},
+			}},
+		}, "func(x int, y int, opts ...string) (int, error)"},
 		{"UnknownExpr", &ast.BadExpr{}, ""},
 	}
 
@@ -193,8 +234,18 @@ func TestGoTypeToTSType(t *testing.T) {
 		{"malformed[", "any"},
 		{"SelfRef", "any"},
 		{"*int", "number | null"},
+		{"[]*int", "(number | null)[]"},
+		{"[]*func(int) string", "(((arg: number) => string) | null)[]"},
+		{"[]*[]*int", "((number | null)[] | null)[]"},
+		{"*[]*func()", "((() => void) | null)[] | null"},
+		{"[]*MyAlias", "(string | null)[]"},
+		{"[][]*BasicPersonInfo", "(BasicPersonInfo | null)[][]"},
 		{"[][]map[int]string", "({ [key: number]: string })[][]"},
-		{"map[string][]*MyAlias", "{ [key: string]: string | null[] }"},
+		{"map[string][]*MyAlias", "{ [key: string]: (string | null)[] }"},
+		{"map[string][]*int", "{ [key: string]: (number | null)[] }"},
+		{"map[int][]*BasicPersonInfo", "{ [key: number]: (BasicPersonInfo | null)[] }"},
+		{"map[string][][]*int", "{ [key: string]: (number | null)[][] }"},
+		{"map[string]map[string][]*int", "{ [key: string]: { [key: string]: (number | null)[] } }"},
 		{"Alias3", "string"},
 		{"MyType[T]", "MyType<T>"},
 		{"Result[K, V]", "Result<K, V>"},
@@ -231,11 +282,20 @@ func TestGoTypeToTSType(t *testing.T) {
 		{"struct{}", "any"},
 		{"error", "Error"},
 		//
-		{"*time.Time", "string"},
-		{"*url.URL", "string"},
+		{"*time.Time", "string | null"},
+		{"*url.URL", "string | null"},
 		{"decimal.Decimal", "string"},
 		{"primitive.ObjectID", "string"},
 		{"primitive.Decimal128", "string"},
+		{"primitive.DateTime", "string"},
+		{"primitive.Timestamp", "number"},
+		{"primitive.Binary", "Uint8Array"},
+		{"big.Int", "number"},
+		{"*big.Int", "number | null"},
+		{"big.Float", "string"},
+		{"*big.Float", "string | null"},
+		{"big.Rat", "string"},
+		{"*primitive.DateTime", "string | null"},
 		{"uuid.UUID", "string"},
 		{"pgtype.UUID", "string"},
 		{"sql.NullString", "string | null"},
@@ -500,3 +560,1899 @@ func TestIsUserDefinedStruct(t *testing.T) {
 		t.Errorf("expected false for undefined struct")
 	}
 }
+
+func TestGoTypeToTSTypeWithConfig_Overrides(t *testing.T) {
+	cfg := &parser.Config{
+		Overrides: map[string]string{
+			"primitive.DateTime": "number",
+			"primitive.Binary":   "string",
+			"big.Int":            "string",
+		},
+	}
+
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"primitive.DateTime", "number"},
+		{"primitive.Binary", "string"},
+		{"primitive.Timestamp", "number"},
+		{"[]primitive.DateTime", "number[]"},
+		{"*primitive.Binary", "string | null"},
+		{"*big.Int", "string | null"},
+		{"big.Rat", "string"},
+	}
+
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType,
+			map[string]string{},
+			nil,
+			map[string]parser.StructInfo{},
+			map[string]string{},
+			map[string]bool{},
+			cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestDownloadModule_Errors(t *testing.T) {
+	if _, err := parser.DownloadModule(""); err == nil {
+		t.Error("expected error for empty module path")
+	}
+	if _, err := parser.DownloadModule("@v1.0.0"); err == nil {
+		t.Error("expected error for module without path")
+	}
+
+	t.Setenv("GOPROXY", "off")
+	if _, err := parser.DownloadModule("example.invalid/go2ts/missing@v1.0.0"); err == nil {
+		t.Error("expected error when the module cannot be fetched")
+	}
+}
+
+func TestParseGoFiles_InterfacesAndMethods(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Event interface {
+	Process() error
+	Name() string
+}
+
+type Empty interface{}
+
+type FooEvent struct{}
+
+func (FooEvent) Process() error { return nil }
+func (*FooEvent) Name() string  { return "foo" }
+
+type Box[T any] struct{ V T }
+
+func (b *Box[T]) Get() T { return b.V }
+
+func helper() {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write model.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	wantIfaces := []parser.GoInterface{{Name: "Event", Methods: []string{"Process", "Name"}}}
+	if !reflect.DeepEqual(data.Interfaces, wantIfaces) {
+		t.Errorf("Interfaces = %#v, want %#v", data.Interfaces, wantIfaces)
+	}
+
+	methods := map[string][]string{}
+	for _, s := range data.Structs {
+		methods[s.Name] = s.Methods
+	}
+	if got := methods["FooEvent"]; !reflect.DeepEqual(got, []string{"Process", "Name"}) {
+		t.Errorf("FooEvent methods = %v", got)
+	}
+	if got := methods["Box"]; !reflect.DeepEqual(got, []string{"Get"}) {
+		t.Errorf("Box methods = %v", got)
+	}
+}
+
+func TestGoTypeToTSType_NestedGenerics(t *testing.T) {
+	aliasMap := map[string]string{
+		"Loop":    "[]Loop",
+		"CycleA":  "GenericResult[CycleB]",
+		"CycleB":  "CycleA",
+		"UserRef": "UserAccount",
+	}
+	structMap := map[string]parser.StructInfo{
+		"GenericResult": {Name: "GenericResult", TypeParams: []string{"T"}},
+		"UserAccount":   {Name: "UserAccount"},
+	}
+
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"GenericResult[GenericResult[*UserAccount]]", "GenericResult<GenericResult<UserAccount | null>>"},
+		{
+			"GenericResult[GenericResult[GenericResult[GenericResult[*UserAccount]]]]",
+			"GenericResult<GenericResult<GenericResult<GenericResult<UserAccount | null>>>>",
+		},
+		{"Pair[Pair[int, int], Pair[int, int]]", "Pair<Pair<number, number>, Pair<number, number>>"},
+		{"GenericResult[UserRef]", "GenericResult<UserAccount>"},
+		{"GenericResult[GenericResult[UserRef]]", "GenericResult<GenericResult<UserAccount>>"},
+		// alias cycles still degrade to any
+		{"Loop", "any[]"},
+		{"CycleA", "GenericResult<any>"},
+	}
+
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, aliasMap, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_Report(t *testing.T) {
+	aliasMap := map[string]string{"SelfRef": "SelfRef", "Key": "struct{ X int }"}
+	report := &parser.Report{Scope: "Model.Field"}
+	cfg := &parser.Config{Report: report}
+
+	tests := []struct {
+		goType  string
+		want    string
+		message string
+	}{
+		{"map[struct{ X, Y int }]string", "{ [key: string]: string }", "map key struct{ X, Y int } coerced to string"},
+		{"map[*Foo]int", "{ [key: string]: number }", "map key *Foo coerced to string"},
+		{"map[string]int", "{ [key: string]: number }", ""},
+		{"map[int]bool", "{ [key: number]: boolean }", ""},
+		{"pkg.Unknown", "any", "unsupported type converted to any"},
+		{"SelfRef", "any", "self-referencing alias converted to any"},
+	}
+
+	for _, tc := range tests {
+		report.Diagnostics = nil
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, aliasMap, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+		lossy := report.Filter(parser.KindLossy)
+		if tc.message == "" {
+			if len(lossy) != 0 {
+				t.Errorf("%q: unexpected diagnostics %v", tc.goType, lossy)
+			}
+			continue
+		}
+		if len(lossy) != 1 || lossy[0].Message != tc.message {
+			t.Errorf("%q: diagnostics = %v, want %q", tc.goType, lossy, tc.message)
+			continue
+		}
+		if lossy[0].Scope != "Model.Field" {
+			t.Errorf("%q: scope = %q", tc.goType, lossy[0].Scope)
+		}
+	}
+}
+
+func TestParseGoFiles_FieldDirectives(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Wire struct {
+	// Raw is nullable on the wire.
+	//go2ts:nullable
+	Raw  string
+	Ptr  *int ` + "`json:\"ptr\"`" + ` //go2ts:nonnull
+	Name string // plain comment
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write model.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 1 {
+		t.Fatalf("expected 1 struct, got %d", len(data.Structs))
+	}
+
+	want := map[string][]string{
+		"Raw":  {"nullable"},
+		"Ptr":  {"nonnull"},
+		"Name": nil,
+	}
+	for _, f := range data.Structs[0].Fields {
+		if !reflect.DeepEqual(f.Directives, want[f.Name]) {
+			t.Errorf("%s directives = %#v, want %#v", f.Name, f.Directives, want[f.Name])
+		}
+	}
+}
+
+func TestParseGoFilesWithOptions_VarStructs(t *testing.T) {
+	dir := t.TempDir()
+	src := `package config
+
+var defaultConfig = struct {
+	Host string ` + "`json:\"host\"`" + `
+	Port int
+}{Host: "localhost", Port: 8080}
+
+var (
+	Limits struct{ Max int }
+	_      = struct{ Ignored bool }{}
+	count  = 3
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write config.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 0 {
+		t.Errorf("expected var structs to be ignored by default, got %+v", data.Structs)
+	}
+
+	data, err = parser.ParseGoFilesWithOptions(dir, parser.ParseGoFilesOptions{VarStructs: true})
+	if err != nil {
+		t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+	}
+	want := []parser.GoStruct{
+		{Name: "DefaultConfig", Fields: []parser.StructField{
+			{Name: "Host", Type: "string", Tags: `json:"host"`},
+			{Name: "Port", Type: "int"},
+		}},
+		{Name: "Limits", Fields: []parser.StructField{{Name: "Max", Type: "int"}}},
+	}
+	if len(data.Structs) != len(want) {
+		t.Fatalf("Structs = %+v, want %+v", data.Structs, want)
+	}
+	for i := range want {
+		got := data.Structs[i]
+		if got.Name != want[i].Name || !reflect.DeepEqual(got.Fields, want[i].Fields) {
+			t.Errorf("Structs[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// a var struct named like a declared type is left out with a diagnostic
+	clash := writeTree(t, map[string]string{
+		"config.go": "package config\n\nvar config = struct{ Debug bool }{}\n\ntype Config struct{ Host string }\n",
+	})
+	report := &parser.Report{}
+	data, err = parser.ParseGoFilesWithOptions(clash, parser.ParseGoFilesOptions{VarStructs: true, Diagnostics: report})
+	if err != nil {
+		t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+	}
+	if len(data.Structs) != 1 || data.Structs[0].Fields[0].Name != "Host" {
+		t.Errorf("expected the declared Config only, got %+v", data.Structs)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].GoType != "config" || !strings.Contains(report.Diagnostics[0].Message, "Config is already declared") {
+		t.Errorf("unexpected diagnostics %v", report.Diagnostics)
+	}
+}
+
+func TestParseGoFiles_Provenance(t *testing.T) {
+	dir := t.TempDir()
+	src := "package model\n\n// User is a user.\ntype User struct {\n\tName string\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "user.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write user.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 1 {
+		t.Fatalf("expected 1 struct, got %d", len(data.Structs))
+	}
+	s := data.Structs[0]
+	if s.Package != "model" {
+		t.Errorf("Package = %q, want %q", s.Package, "model")
+	}
+	if filepath.Base(s.Pos.Filename) != "user.go" || s.Pos.Line != 4 {
+		t.Errorf("Pos = %v, want user.go:4", s.Pos)
+	}
+}
+
+func TestGoTypeToTSType_URL(t *testing.T) {
+	convert := func(goType string, cfg *parser.Config) string {
+		return parser.GoTypeToTSTypeWithConfig(goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+	}
+
+	// by pointer is by value or null
+	for _, cfg := range []*parser.Config{nil, {URLAsObject: true}} {
+		if byValue, byPtr := convert("url.URL", cfg), convert("*url.URL", cfg); byPtr != byValue+" | null" {
+			t.Errorf("url.URL = %q but *url.URL = %q (cfg %+v)", byValue, byPtr, cfg)
+		}
+	}
+
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"url.URL", nil, "string"},
+		{"*url.URL", nil, "string | null"},
+		{"[]url.URL", nil, "string[]"},
+		{"url.URL", &parser.Config{URLAsObject: true}, parser.URLObjectType},
+		{"*url.URL", &parser.Config{URLAsObject: true}, parser.URLObjectType + " | null"},
+		{"[]*url.URL", &parser.Config{URLAsObject: true}, "(" + parser.URLObjectType + " | null)[]"},
+	}
+	for _, tc := range tests {
+		if got := convert(tc.goType, tc.cfg); got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSType_GenericContainers(t *testing.T) {
+	structMap := map[string]parser.StructInfo{
+		"OrderedMap": {Name: "OrderedMap", TypeParams: []string{"K", "V"}},
+		"Set":        {Name: "Set", TypeParams: []string{"T"}},
+		"Plain":      {Name: "Plain"},
+	}
+	report := &parser.Report{}
+	cfg := &parser.Config{Report: report}
+
+	tests := []struct {
+		goType  string
+		want    string
+		message string
+	}{
+		{"OrderedMap[string, int]", "OrderedMap<string, number>", ""},
+		{"[]OrderedMap[string, *Plain]", "OrderedMap<string, Plain | null>[]", ""},
+		{"map[string]Set[int64]", "{ [key: string]: Set<number> }", ""},
+		{"OrderedMap[string]", "OrderedMap<string>", "generic type OrderedMap expects 2 type arguments, got 1"},
+		{"atomic.Pointer[Plain]", "any", "generic type from unscanned package converted to any"},
+	}
+
+	for _, tc := range tests {
+		report.Diagnostics = nil
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			structMap, map[string]string{}, map[string]bool{}, cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+		var messages []string
+		for _, d := range report.Diagnostics {
+			messages = append(messages, d.Message)
+		}
+		if (tc.message == "" && len(messages) != 0) || (tc.message != "" && !reflect.DeepEqual(messages, []string{tc.message})) {
+			t.Errorf("%q: diagnostics = %v, want %q", tc.goType, messages, tc.message)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_KeepNames(t *testing.T) {
+	aliasMap := map[string]string{"Event": "interface{}", "Email": "string"}
+	cfg := &parser.Config{KeepNames: map[string]bool{"Event": true}}
+
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"Event", "Event"},
+		{"[]Event", "Event[]"},
+		{"map[string]Event", "{ [key: string]: Event }"},
+		{"Email", "string"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, aliasMap, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_ReadonlyArrays(t *testing.T) {
+	cfg := &parser.Config{ReadonlyArrays: true}
+
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"[]string", "readonly string[]"},
+		{"[][]int", "readonly (readonly number[])[]"},
+		{"[][][]bool", "readonly (readonly (readonly boolean[])[])[]"},
+		{"[]map[int]string", "readonly ({ [key: number]: string })[]"},
+		{"map[string][]int", "{ [key: string]: readonly number[] }"},
+		{"[]byte", "Uint8Array"},
+		{"Box[[]int]", "Box<readonly number[]>"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_RuneAsString(t *testing.T) {
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"rune", nil, "number"},
+		{"rune", &parser.Config{}, "number"},
+		{"rune", &parser.Config{RuneAsString: true}, "string"},
+		{"*rune", &parser.Config{RuneAsString: true}, "string | null"},
+		{"int32", &parser.Config{RuneAsString: true}, "number"},
+		{"byte", &parser.Config{RuneAsString: true}, "number"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_Sets(t *testing.T) {
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"map[string]struct{}", nil, "{ [key: string]: any }"},
+		{"map[string]struct{}", &parser.Config{Sets: parser.SetAsSet}, "Set<string>"},
+		{"map[int]struct{}", &parser.Config{Sets: parser.SetAsSet}, "Set<number>"},
+		{"map[string]struct{}", &parser.Config{Sets: parser.SetAsRecord}, "{ [key: string]: true }"},
+		{"map[string]bool", &parser.Config{Sets: parser.SetAsSet}, "{ [key: string]: boolean }"},
+		{"map[string]struct{ Name string }", &parser.Config{Sets: parser.SetAsSet}, "{ [key: string]: { Name: string } }"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_RuneSliceAsString(t *testing.T) {
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"[]rune", nil, "number[]"},
+		{"[]rune", &parser.Config{RuneSliceAsString: true}, "string"},
+		{"[]int32", &parser.Config{RuneSliceAsString: true}, "number[]"},
+		{"[]byte", &parser.Config{RuneSliceAsString: true}, "Uint8Array"},
+		{"[]byte", nil, "Uint8Array"},
+		{"*[]rune", &parser.Config{RuneSliceAsString: true}, "string | null"},
+		{"[][]rune", &parser.Config{RuneSliceAsString: true}, "string[]"},
+		{"[]rune", &parser.Config{RuneAsString: true}, "string[]"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSType_AliasOfGenericOverAlias(t *testing.T) {
+	aliasMap := map[string]string{
+		"AliasMapType":       "map[string]string",
+		"AliasMapResultType": "GenericResult[AliasMapType]",
+		"ChainedResultType":  "AliasMapResultType",
+	}
+	structMap := map[string]parser.StructInfo{
+		"GenericResult": {Name: "GenericResult", TypeParams: []string{"T"}},
+	}
+	const expanded = "GenericResult<{ [key: string]: string }>"
+
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"AliasMapResultType", nil, expanded},
+		{"ChainedResultType", nil, expanded},
+		{"*AliasMapResultType", nil, expanded + " | null"},
+		{"[]AliasMapResultType", nil, expanded + "[]"},
+		{"map[string]AliasMapResultType", nil, "{ [key: string]: " + expanded + " }"},
+		{"GenericResult[AliasMapResultType]", nil, "GenericResult<" + expanded + ">"},
+		{
+			"AliasMapResultType",
+			&parser.Config{KeepNames: map[string]bool{"AliasMapResultType": true}},
+			"AliasMapResultType",
+		},
+		{
+			"ChainedResultType",
+			&parser.Config{KeepNames: map[string]bool{"AliasMapResultType": true}},
+			"AliasMapResultType",
+		},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, aliasMap, nil, structMap, map[string]string{}, map[string]bool{}, tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+
+		// CheckGenericPatterns agrees with the full conversion for alias references
+		if tc.cfg != nil {
+			continue
+		}
+		got = parser.CheckGenericPatterns(tc.goType, aliasMap, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("CheckGenericPatterns(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFiles_DefinedTypes(t *testing.T) {
+	dir := filepath.Join("..", "..", "test", "testdata", "model")
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	want := map[string]bool{
+		"CustomInt":    true,
+		"UserStatus":   true,
+		"AliasIntType": false,
+		"AliasMapType": false,
+	}
+	aliasMap := map[string]string{}
+	for _, alias := range data.Aliases {
+		aliasMap[alias.Name] = alias.Underlying
+		if defined, ok := want[alias.Name]; ok && alias.Defined != defined {
+			t.Errorf("%s: Defined = %v, want %v", alias.Name, alias.Defined, defined)
+		}
+	}
+
+	tests := map[string]string{
+		"map[CustomInt]string":      "{ [key: number]: string }",
+		"map[UserStatus]string":     "{ [key: number]: string }",
+		"map[AliasIntType]bool":     "{ [key: number]: boolean }",
+		"map[*CustomInt]string":     "{ [key: string]: string }",
+		"map[CustomInt][]CustomInt": "{ [key: number]: number[] }",
+	}
+	for goType, want := range tests {
+		got := parser.GoTypeToTSType(goType, aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", goType, got, want)
+		}
+	}
+}
+
+func TestParseGoFiles_Embeds(t *testing.T) {
+	dir := filepath.Join("..", "..", "test", "testdata", "model")
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	want := map[string][]string{
+		"EmbeddedOnly":           {"*EmbeddedType"},
+		"AnonymousEmbeddedBasic": {"*BasicPersonInfo"},
+		"StructBWithConflict":    {"StructAWithField"},
+		"EmptyStruct":            nil,
+	}
+	for _, s := range data.Structs {
+		embeds, ok := want[s.Name]
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(s.Embeds, embeds) {
+			t.Errorf("%s: Embeds = %v, want %v", s.Name, s.Embeds, embeds)
+		}
+		if s.Name == "EmbeddedOnly" && len(s.Fields) != 0 {
+			t.Errorf("EmbeddedOnly: Fields = %v, want none", s.Fields)
+		}
+	}
+}
+
+func TestParseGoFiles_Enums(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Color string
+
+const (
+	ColorRed   Color = "red"
+	ColorGreen Color = "green"
+)
+
+type Flag uint8
+
+const (
+	FlagRead Flag = 1 << iota
+	_
+	FlagExec
+)
+
+type Level int
+
+const (
+	LevelLow = Level(iota + 1)
+	LevelHigh
+	LevelUnknown Level = -1
+	maxLevel = 10
+)
+
+const Undeclared NotScanned = 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "enums.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write enums.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	want := []parser.GoEnum{
+		{Name: "Color", BaseType: "string", Members: []parser.EnumMember{
+			{Name: "ColorRed", Value: `"red"`},
+			{Name: "ColorGreen", Value: `"green"`},
+		}},
+		{Name: "Flag", BaseType: "uint8", Members: []parser.EnumMember{
+			{Name: "FlagRead", Value: "1"},
+			{Name: "FlagExec", Value: "4"},
+		}},
+		{Name: "Level", BaseType: "int", Members: []parser.EnumMember{
+			{Name: "LevelLow", Value: "1"},
+			{Name: "LevelHigh", Value: "2"},
+			{Name: "LevelUnknown", Value: "-1"},
+		}},
+	}
+	if !reflect.DeepEqual(data.Enums, want) {
+		t.Errorf("Enums = %+v\nwant %+v", data.Enums, want)
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_Unresolvable(t *testing.T) {
+	tests := []struct {
+		goType  string
+		want    string
+		message string
+	}{
+		{"", "", ""},
+		{"*", "never | null", "unresolvable type converted to never"},
+		{"[]", "never[]", "unresolvable type converted to never"},
+		{"map[string]", "{ [key: string]: never }", "unresolvable type converted to never"},
+		{"Foo[]", "any", "malformed generic type converted to any"},
+	}
+	for _, tc := range tests {
+		report := &parser.Report{}
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, &parser.Config{Report: report})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+		var messages []string
+		for _, d := range report.Diagnostics {
+			messages = append(messages, d.Message)
+		}
+		if (tc.message == "" && len(messages) != 0) || (tc.message != "" && !reflect.DeepEqual(messages, []string{tc.message})) {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) diagnostics = %v, want %q", tc.goType, messages, tc.message)
+		}
+	}
+}
+
+func TestGoTypeToTSType_TimePointers(t *testing.T) {
+	structMap := map[string]parser.StructInfo{
+		"GenericResult": {Name: "GenericResult", TypeParams: []string{"T"}},
+	}
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"time.Time", "string"},
+		{"*time.Time", "string | null"},
+		{"[]time.Time", "string[]"},
+		{"[]*time.Time", "(string | null)[]"},
+		{"map[string]*time.Time", "{ [key: string]: (string | null) }"},
+		{"GenericResult[*time.Time]", "GenericResult<string | null>"},
+		{"GenericResult[[]*time.Time]", "GenericResult<(string | null)[]>"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_Trace(t *testing.T) {
+	trace := &parser.Trace{Scope: "User.Friends"}
+	aliasMap := map[string]string{"UserID": "string"}
+	got := parser.GoTypeToTSTypeWithConfig("map[UserID][]*User", aliasMap, nil,
+		map[string]parser.StructInfo{"User": {Name: "User"}}, map[string]string{}, map[string]bool{},
+		&parser.Config{Trace: trace})
+	if want := "{ [key: string]: (User | null)[] }"; got != want {
+		t.Fatalf("GoTypeToTSTypeWithConfig = %q, want %q", got, want)
+	}
+
+	want := []parser.TraceStep{
+		{Scope: "User.Friends", Depth: 0, GoType: "map[UserID][]*User", TSType: "{ [key: string]: (User | null)[] }"},
+		{Scope: "User.Friends", Depth: 1, GoType: "string", TSType: "string"},
+		{Scope: "User.Friends", Depth: 1, GoType: "[]*User", TSType: "(User | null)[]"},
+		{Scope: "User.Friends", Depth: 2, GoType: "*User", TSType: "User | null"},
+		{Scope: "User.Friends", Depth: 3, GoType: "User", TSType: "User"},
+	}
+	if !reflect.DeepEqual(trace.Steps, want) {
+		t.Errorf("Steps = %+v\nwant %+v", trace.Steps, want)
+	}
+
+	wantString := "User.Friends:\n" +
+		"  map[UserID][]*User → { [key: string]: (User | null)[] }\n" +
+		"    string → string\n" +
+		"    []*User → (User | null)[]\n" +
+		"      *User → User | null\n" +
+		"        User → User\n"
+	if s := trace.String(); s != wantString {
+		t.Errorf("String() = %q, want %q", s, wantString)
+	}
+}
+
+func TestGoTypeToTSType_GenericMapPointerGrouping(t *testing.T) {
+	structMap := map[string]parser.StructInfo{
+		"GenericResult":     {Name: "GenericResult", TypeParams: []string{"T"}},
+		"UserProfileDetail": {Name: "UserProfileDetail"},
+	}
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"GenericResult[map[string]*UserProfileDetail]", "GenericResult<{ [key: string]: (UserProfileDetail | null) }>"},
+		{"*GenericResult[map[string]*UserProfileDetail]", "GenericResult<{ [key: string]: (UserProfileDetail | null) }> | null"},
+		{"GenericResult[map[string][]*UserProfileDetail]", "GenericResult<{ [key: string]: (UserProfileDetail | null)[] }>"},
+		{"map[string]GenericResult[*UserProfileDetail]", "{ [key: string]: GenericResult<UserProfileDetail | null> }"},
+		{"map[string]*GenericResult[*UserProfileDetail]", "{ [key: string]: (GenericResult<UserProfileDetail | null> | null) }"},
+		{"GenericResult[map[string]GenericResult[*UserProfileDetail]]",
+			"GenericResult<{ [key: string]: GenericResult<UserProfileDetail | null> }>"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFiles_TypeSets(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Key interface{ ~string }
+
+type Reading struct {
+	Value interface{ ~int | ~string } ` + "`json:\"value\"`" + `
+	Any   interface{}                  ` + "`json:\"any\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, alias := range data.Aliases {
+		got[alias.Name] = alias.Underlying
+	}
+	for _, field := range data.Structs[0].Fields {
+		got[field.Name] = field.Type
+	}
+
+	tests := map[string]struct{ goType, ts string }{
+		"Number": {"interface{ ~int | ~int64 | ~float64 }", "number"},
+		"Key":    {"interface{ ~string }", "string"},
+		"Value":  {"interface{ ~int | ~string }", "number | string"},
+		"Any":    {"interface{}", "any"},
+	}
+	for name, want := range tests {
+		if got[name] != want.goType {
+			t.Errorf("%s: type = %q, want %q", name, got[name], want.goType)
+		}
+		ts := parser.GoTypeToTSType(got[name], map[string]string{}, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if ts != want.ts {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", got[name], ts, want.ts)
+		}
+	}
+}
+
+func TestGoTypeToTSType_AnonymousStructContainers(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"[]struct{ ID int; Name string }", "{ ID: number; Name: string }[]"},
+		{"map[string]struct{ ID int; Name string }", "{ [key: string]: { ID: number; Name: string } }"},
+		{"[]*struct{ ID int }", "({ ID: number } | null)[]"},
+		{"[][]struct{ X, Y float64 }", "{ X: number; Y: number }[][]"},
+		{"map[string][]struct{ Tags []string }", "{ [key: string]: { Tags: string[] }[] }"},
+		{"[]struct{ Owner struct{ ID int; Name string }; Active bool }",
+			"{ Owner: { ID: number; Name: string }; Active: boolean }[]"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestPackageDir(t *testing.T) {
+	tests := map[string]bool{
+		"github.com/limbicnode/go2ts/internal/parser": true,
+		"net/http":                  false,
+		"internal/modle":            false,
+		".":                         false,
+		"./missing":                 false,
+		"../parser":                 false,
+		"/abs/path":                 false,
+		filepath.Join("..", ".."):   false,
+		"testdata_that_is_not_here": false,
+	}
+	for in, want := range tests {
+		if got := parser.IsImportPath(in); got != want {
+			t.Errorf("IsImportPath(%q) = %v, want %v", in, got, want)
+		}
+	}
+
+	dir, err := parser.PackageDir("github.com/limbicnode/go2ts/internal/parser")
+	if err != nil {
+		t.Fatalf("PackageDir failed: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != wd {
+		t.Errorf("PackageDir = %q, want %q", dir, wd)
+	}
+
+	t.Setenv("GOPROXY", "off")
+	if _, err := parser.PackageDir("example.invalid/go2ts/missing"); err == nil {
+		t.Error("expected error for a package that cannot be found")
+	}
+
+	// a module path without a dot is recognized within its module
+	if err := os.Chdir(writeTree(t, map[string]string{"go.mod": "module myapp\n"})); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	for in, want := range map[string]bool{"myapp/internal/model": true, "myapp": true, "myapplet/model": false} {
+		if got := parser.IsImportPath(in); got != want {
+			t.Errorf("IsImportPath(%q) in module myapp = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestGoTypeToTSType_PointerToGeneric(t *testing.T) {
+	structMap := map[string]parser.StructInfo{
+		"GenericResult": {Name: "GenericResult", TypeParams: []string{"T"}},
+		"UserAccount":   {Name: "UserAccount"},
+	}
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"*GenericResult[*UserAccount]", "GenericResult<UserAccount | null> | null"},
+		{"**GenericResult[*UserAccount]", "GenericResult<UserAccount | null> | null"},
+		{"[]*GenericResult[*UserAccount]", "(GenericResult<UserAccount | null> | null)[]"},
+		{"map[string]*GenericResult[*UserAccount]", "{ [key: string]: (GenericResult<UserAccount | null> | null) }"},
+		{"*GenericResult[*GenericResult[*UserAccount]]", "GenericResult<GenericResult<UserAccount | null> | null> | null"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_JSONAccurateMapKeys(t *testing.T) {
+	aliasMap := map[string]string{"UserID": "int64"}
+	tests := []struct {
+		goType   string
+		number   string
+		accurate string
+	}{
+		{"map[int]string", "{ [key: number]: string }", "{ [key: string]: string }"},
+		{"map[uint8][]bool", "{ [key: number]: boolean[] }", "{ [key: string]: boolean[] }"},
+		{"map[UserID]int", "{ [key: number]: number }", "{ [key: string]: number }"},
+		{"map[string]map[int]string", "{ [key: string]: { [key: number]: string } }", "{ [key: string]: { [key: string]: string } }"},
+		{"map[string]int", "{ [key: string]: number }", "{ [key: string]: number }"},
+	}
+	for _, tc := range tests {
+		for _, mode := range []struct {
+			accurate bool
+			want     string
+		}{{false, tc.number}, {true, tc.accurate}} {
+			cfg := &parser.Config{JSONAccurateMapKeys: mode.accurate}
+			got := parser.GoTypeToTSTypeWithConfig(tc.goType, aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+			if got != mode.want {
+				t.Errorf("GoTypeToTSTypeWithConfig(%q, JSONAccurateMapKeys=%v) = %q, want %q", tc.goType, mode.accurate, got, mode.want)
+			}
+		}
+	}
+}
+
+func TestParseGoFiles_FuncAliases(t *testing.T) {
+	dir := t.TempDir()
+	src := `package handlers
+
+type Handler = func(int) string
+
+type Middleware func(next Handler) Handler
+
+type Hook func()
+
+type Visitor = func(path string, depth int, tags ...string) (bool, error)
+`
+	if err := os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	aliasMap := map[string]string{}
+	for _, alias := range data.Aliases {
+		aliasMap[alias.Name] = alias.Underlying
+	}
+	tests := map[string]struct{ underlying, ts string }{
+		"Handler":    {"func(int) string", "(arg: number) => string"},
+		"Middleware": {"func(next Handler) Handler", "(next: (arg: number) => string) => (arg: number) => string"},
+		"Hook":       {"func()", "() => void"},
+		"Visitor": {
+			"func(path string, depth int, tags ...string) (bool, error)",
+			"(path: string, depth: number, ...tags: string[]) => [boolean, Error]",
+		},
+	}
+	for name, want := range tests {
+		if aliasMap[name] != want.underlying {
+			t.Errorf("%s: Underlying = %q, want %q", name, aliasMap[name], want.underlying)
+		}
+		got := parser.GoTypeToTSType(aliasMap[name], aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != want.ts {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", aliasMap[name], got, want.ts)
+		}
+	}
+
+	for goType, want := range map[string]string{
+		"[]func(int) string": "((arg: number) => string)[]",
+		"*func()":            "(() => void) | null",
+		"*func() *int":       "(() => number | null) | null",
+		"func(int)":          "(arg: number) => void",
+	} {
+		got := parser.GoTypeToTSType(goType, aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", goType, got, want)
+		}
+	}
+}
+
+func TestGoTypeToTSType_SQLNull(t *testing.T) {
+	structMap := map[string]parser.StructInfo{"UserAccount": {Name: "UserAccount"}}
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"sql.Null[string]", "string | null"},
+		{"sql.Null[bool]", "boolean | null"},
+		{"sql.Null[int]", "number | null"},
+		{"sql.Null[time.Time]", "string | null"},
+		{"sql.Null[UserAccount]", "UserAccount | null"},
+		{"sql.Null[*string]", "string | null"},
+		{"[]sql.Null[string]", "(string | null)[]"},
+		{"*sql.Null[int64]", "number | null"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFiles_InlineStructFieldTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Item struct{ ID int }
+
+type Envelope struct {
+	Meta struct {
+		Inner  *int
+		Items  []*Item
+		Index  map[string]*Item
+		Matrix [][]float64
+		Nested *struct{ Count int }
+		OnDone func(id int, err error)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	var meta string
+	for _, s := range data.Structs {
+		if s.Name == "Envelope" {
+			meta = s.Fields[0].Type
+		}
+	}
+
+	structMap := map[string]parser.StructInfo{"Item": {Name: "Item"}}
+	got := parser.ParseStructType(meta, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+	want := "{ Inner: number | null; " +
+		"Items: (Item | null)[]; " +
+		"Index: { [key: string]: (Item | null) }; " +
+		"Matrix: number[][]; " +
+		"Nested: { Count: number } | null; " +
+		"OnDone: (id: number, err: Error) => void }"
+	if got != want {
+		t.Errorf("ParseStructType(%q) =\n%q\nwant\n%q", meta, got, want)
+	}
+}
+
+func TestParseGoFilesWithOptions_LocalTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package api
+
+type Server struct{}
+
+func (s *Server) Stats() any {
+	type stats struct {
+		Requests int ` + "`json:\"requests\"`" + `
+	}
+	return stats{}
+}
+
+func handler() {
+	go func() {
+		type event struct{ Name string }
+		_ = event{}
+	}()
+	type Server struct{ Shadow bool }
+	type ids []int
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(data parser.GoFileData) []string {
+		var names []string
+		for _, s := range data.Structs {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if got := names(data); !reflect.DeepEqual(got, []string{"Server"}) {
+		t.Errorf("local types should be opt-in, got %v", got)
+	}
+
+	data, err = parser.ParseGoFilesWithOptions(dir, parser.ParseGoFilesOptions{LocalTypes: true})
+	if err != nil {
+		t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+	}
+	if got, want := names(data), []string{"Server", "stats", "event"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("structs = %v, want %v", got, want)
+	}
+	if len(data.Aliases) != 0 {
+		t.Errorf("local non-struct types should be left out, got %v", data.Aliases)
+	}
+	if fields := data.Structs[1].Fields; len(fields) != 1 || fields[0].Type != "int" {
+		t.Errorf("unexpected fields of stats: %+v", fields)
+	}
+
+	// a local type declared before the top-level type of its name is skipped,
+	// and identical local types in several functions are converted once
+	dir = t.TempDir()
+	src = `package api
+
+func handler() {
+	type Server struct{ Shadow bool }
+	type page struct{ Size int }
+}
+
+func other() {
+	type page struct{ Size int }
+}
+
+type Server struct{ Addr string }
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err = parser.ParseGoFilesWithOptions(dir, parser.ParseGoFilesOptions{LocalTypes: true})
+	if err != nil {
+		t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+	}
+	if got, want := names(data), []string{"Server", "page"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("structs = %v, want %v", got, want)
+	}
+	if fields := data.Structs[0].Fields; len(fields) != 1 || fields[0].Name != "Addr" {
+		t.Errorf("expected the top-level Server, got fields %+v", fields)
+	}
+
+	// local types of the same name declared differently are ambiguous
+	src = `package api
+
+func a() { type page struct{ Size int } }
+
+func b() { type page struct{ Cursor string } }
+`
+	if err := os.WriteFile(filepath.Join(dir, "api.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = parser.ParseGoFilesWithOptions(dir, parser.ParseGoFilesOptions{LocalTypes: true})
+	if err == nil || !strings.Contains(err.Error(), `local struct "page" is declared differently`) {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+}
+
+func TestParseGoFiles_FixedArrays(t *testing.T) {
+	dir := t.TempDir()
+	src := `package crypto
+
+const Size = 4
+
+type Hash [32]byte
+
+type Key struct {
+	Digest [32]byte
+	Nonce  [16]byte
+	Salt   [Size]uint8
+	Coords [3]float64
+	Grid   [2][2]int
+	Sum    Hash
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "crypto.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 1 {
+		t.Fatalf("expected 1 struct, got %d", len(data.Structs))
+	}
+	aliasMap := map[string]string{}
+	for _, alias := range data.Aliases {
+		aliasMap[alias.Name] = alias.Underlying
+	}
+
+	tests := map[string]struct {
+		goType  string
+		ts      string
+		numbers string
+	}{
+		"Digest": {"[32]byte", "string", "number[]"},
+		"Nonce":  {"[16]byte", "string", "number[]"},
+		"Salt":   {"[Size]uint8", "string", "number[]"},
+		"Coords": {"[3]float64", "number[]", "number[]"},
+		"Grid":   {"[2][2]int", "number[][]", "number[][]"},
+		"Sum":    {"Hash", "string", "number[]"},
+	}
+	for _, f := range data.Structs[0].Fields {
+		want := tests[f.Name]
+		if f.Type != want.goType {
+			t.Errorf("%s: Type = %q, want %q", f.Name, f.Type, want.goType)
+		}
+		got := parser.GoTypeToTSType(f.Type, aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != want.ts {
+			t.Errorf("%s: GoTypeToTSType(%q) = %q, want %q", f.Name, f.Type, got, want.ts)
+		}
+		cfg := &parser.Config{ByteArraysAsNumbers: true}
+		got = parser.GoTypeToTSTypeWithConfig(f.Type, aliasMap, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, cfg)
+		if got != want.numbers {
+			t.Errorf("%s: ByteArraysAsNumbers: GoTypeToTSType(%q) = %q, want %q", f.Name, f.Type, got, want.numbers)
+		}
+	}
+}
+
+func TestParseGoFiles_TypeParamConstraints(t *testing.T) {
+	dir := t.TempDir()
+	src := `package stats
+
+type Pair[A any, B int] struct {
+	First  A
+	Second B
+}
+
+type Sorted[S ~[]E, E comparable] = S
+
+type Sum[N ~int | ~float64, M interface{ ~int }] struct{}
+`
+	if err := os.WriteFile(filepath.Join(dir, "stats.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	got := map[string][]string{}
+	for _, s := range data.Structs {
+		got[s.Name] = s.Constraints
+	}
+	for _, a := range data.Aliases {
+		got[a.Name] = a.Constraints
+	}
+	want := map[string][]string{
+		"Pair":   {"any", "int"},
+		"Sorted": {"interface{ ~[]E }", "comparable"},
+		"Sum":    {"interface{ ~int | ~float64 }", "interface{ ~int }"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Constraints = %v, want %v", got, want)
+	}
+}
+
+func TestGoTypeToTSType_RawMessage(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"json.RawMessage", "unknown"},
+		{"*json.RawMessage", "unknown | null"},
+		{"[]json.RawMessage", "unknown[]"},
+		{"map[string]json.RawMessage", "{ [key: string]: unknown }"},
+		{"map[string][]json.RawMessage", "{ [key: string]: unknown[] }"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSType(tc.goType, map[string]string{}, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{})
+		if got != tc.want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFiles_TaggedEmbeddedFields(t *testing.T) {
+	dir := t.TempDir()
+	src := `package dto
+
+import "time"
+
+type Audit struct{ By string }
+
+type Paging struct{ Page int }
+
+type Meta struct{ Version int }
+
+type Order struct {
+	Audit  ` + "`json:\"audit,omitempty\"`" + `
+	*Paging ` + "`json:\"paging\"`" + `
+	Meta   ` + "`json:\",omitempty\"`" + `
+	time.Location ` + "`json:\"-\"`" + `
+	ID     int ` + "`json:\"id\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "dto.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	var order parser.GoStruct
+	for _, s := range data.Structs {
+		if s.Name == "Order" {
+			order = s
+		}
+	}
+	wantFields := []parser.StructField{
+		{Name: "Audit", Type: "Audit", Tags: `json:"audit,omitempty"`},
+		{Name: "Paging", Type: "*Paging", Tags: `json:"paging"`},
+		{Name: "Location", Type: "time.Location", Tags: `json:"-"`},
+		{Name: "ID", Type: "int", Tags: `json:"id"`},
+	}
+	if !reflect.DeepEqual(order.Fields, wantFields) {
+		t.Errorf("Fields = %+v, want %+v", order.Fields, wantFields)
+	}
+	if want := []string{"Meta"}; !reflect.DeepEqual(order.Embeds, want) {
+		t.Errorf("Embeds = %v, want %v", order.Embeds, want)
+	}
+}
+
+func TestParseGoFiles_InterfaceFieldTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+import "io"
+
+type Box[T any] struct{ Value T }
+
+type Holder struct {
+	Named    interface{ Name() string }
+	Embedded interface {
+		io.Reader
+		Close() error
+	}
+	Empty  interface{}
+	Approx interface{ ~string }
+	Union  interface{ ~int | ~float64 }
+	List   []interface{ Close() error }
+	Boxed  Box[interface{ ~int }]
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	structMap := map[string]parser.StructInfo{}
+	var holder parser.GoStruct
+	for _, s := range data.Structs {
+		structMap[s.Name] = parser.StructInfo{Name: s.Name, TypeParams: s.TypeParams}
+		if s.Name == "Holder" {
+			holder = s
+		}
+	}
+	want := map[string]string{
+		"Named":    "any",
+		"Embedded": "any",
+		"Empty":    "any",
+		"Approx":   "string",
+		"Union":    "number",
+		"List":     "any[]",
+		"Boxed":    "Box<number>",
+	}
+	for _, f := range holder.Fields {
+		got := parser.GoTypeToTSType(f.Type, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != want[f.Name] {
+			t.Errorf("%s (%s): got %q, want %q", f.Name, f.Type, got, want[f.Name])
+		}
+	}
+
+	for goType, want := range map[string]string{
+		"interface{ String() string }":           "any",
+		"interface{ ~int; String() string }":     "any",
+		"[]interface{ Close() error }":           "any[]",
+		"map[string]interface{ Close() error }":  "{ [key: string]: any }",
+		"Box[~int]":                              "Box<number>",
+		"Box[interface{ ~string | ~int }]":       "Box<string | number>",
+		"*interface{ Name() string; Age() int }": "any | null",
+	} {
+		got := parser.GoTypeToTSType(goType, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != want {
+			t.Errorf("GoTypeToTSType(%q) = %q, want %q", goType, got, want)
+		}
+	}
+}
+
+func TestParseGoFilesWithOptions_SkippedDirs(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"model.go":                   "package model\n\ntype User struct{ ID int }\n",
+		"sub/order.go":               "package sub\n\ntype Order struct{ ID int }\n",
+		"vendor/dep/dep.go":          "package dep\n\ntype Vendored struct{ ID int }\n",
+		"node_modules/pkg/pkg.go":    "package pkg\n\ntype NodeModule struct{ ID int }\n",
+		"testdata/fixture.go":        "package testdata\n\ntype Fixture struct{ ID int }\n",
+		".git/hooks/hook.go":         "package hooks\n\ntype Hidden struct{ ID int }\n",
+		"sub/vendor/nested/inner.go": "package nested\n\ntype NestedVendored struct{ ID int }\n",
+	})
+
+	structNames := func(opts parser.ParseGoFilesOptions) []string {
+		data, err := parser.ParseGoFilesWithOptions(dir, opts)
+		if err != nil {
+			t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+		}
+		var names []string
+		for _, s := range data.Structs {
+			names = append(names, s.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if got, want := structNames(parser.ParseGoFilesOptions{}), []string{"Order", "User"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default: structs = %v, want %v", got, want)
+	}
+	want := []string{"Fixture", "Hidden", "NestedVendored", "NodeModule", "Order", "User", "Vendored"}
+	if got := structNames(parser.ParseGoFilesOptions{AllDirs: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllDirs: structs = %v, want %v", got, want)
+	}
+
+	// the scanned directory itself is never skipped
+	data, err := parser.ParseGoFiles(filepath.Join(dir, "vendor"))
+	if err != nil || len(data.Structs) != 1 {
+		t.Errorf("expected the vendored struct when scanning vendor itself, got %v, %v", data.Structs, err)
+	}
+}
+
+func TestParseGoFiles_FieldComments(t *testing.T) {
+	dir := t.TempDir()
+	src := `package dto
+
+type Person struct {
+	// Name is the full name.
+	// It may contain spaces.
+	Name string
+	Age  int // years
+	// Nickname is optional.
+	//go2ts:nullable
+	Nickname string // a short name
+	ID       int    //go2ts:nonnull
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "dto.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 1 {
+		t.Fatalf("expected 1 struct, got %d", len(data.Structs))
+	}
+
+	type comments struct{ Doc, Comment string }
+	want := []comments{
+		{Doc: "Name is the full name.\nIt may contain spaces."},
+		{Comment: "years"},
+		{Doc: "Nickname is optional.", Comment: "a short name"},
+		{},
+	}
+	var got []comments
+	for _, f := range data.Structs[0].Fields {
+		got = append(got, comments{f.Doc, f.Comment})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("comments = %q, want %q", got, want)
+	}
+}
+
+func TestParseGoFiles_ChanFields(t *testing.T) {
+	dir := t.TempDir()
+	src := `package jobs
+
+type Job struct{ ID int }
+
+type JobWorker struct {
+	JobChan  chan Job
+	PtrChan  chan *Job
+	Names    <-chan string
+	Results  chan<- []Job
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "jobs.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	structMap := map[string]parser.StructInfo{}
+	var worker parser.GoStruct
+	for _, s := range data.Structs {
+		structMap[s.Name] = parser.StructInfo{TypeParams: s.TypeParams}
+		if s.Name == "JobWorker" {
+			worker = s
+		}
+	}
+
+	want := map[string]string{
+		"JobChan": "AsyncIterable<Job>",
+		"PtrChan": "AsyncIterable<Job | null>",
+		"Names":   "AsyncIterable<string>",
+		"Results": "AsyncIterable<Job[]>",
+	}
+	if len(worker.Fields) != len(want) {
+		t.Fatalf("expected %d fields, got %+v", len(want), worker.Fields)
+	}
+	for _, f := range worker.Fields {
+		got := parser.GoTypeToTSType(f.Type, map[string]string{}, nil, structMap, map[string]string{}, map[string]bool{})
+		if got != want[f.Name] {
+			t.Errorf("%s (%s) = %q, want %q", f.Name, f.Type, got, want[f.Name])
+		}
+	}
+}
+
+func TestParseGoFiles_ModelEnums(t *testing.T) {
+	data, err := parser.ParseGoFiles(filepath.Join("..", "..", "test", "testdata", "model"))
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	want := map[string][]parser.EnumMember{
+		"UserStatus": {
+			{Name: "StatusActive", Value: "0"},
+			{Name: "StatusInactive", Value: "1"},
+			{Name: "StatusSuspended", Value: "2"},
+		},
+		"PaymentMethod": {
+			{Name: "PaymentCreditCard", Value: "0"},
+			{Name: "PaymentPayPal", Value: "1"},
+			{Name: "PaymentToss", Value: "2"},
+			{Name: "PaymentOther", Value: "3"},
+		},
+	}
+	for _, e := range data.Enums {
+		members, ok := want[e.Name]
+		if !ok {
+			continue
+		}
+		delete(want, e.Name)
+		if e.BaseType != "int" || !reflect.DeepEqual(e.Members, members) {
+			t.Errorf("%s = %+v, want int enum of %+v", e.Name, e, members)
+		}
+	}
+	for name := range want {
+		t.Errorf("enum %s not found", name)
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_TimeAsDate(t *testing.T) {
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"time.Time", nil, "string"},
+		{"*time.Time", nil, "string | null"},
+		{"[]time.Time", nil, "string[]"},
+		{"time.Time", &parser.Config{TimeAsDate: true}, "Date"},
+		{"*time.Time", &parser.Config{TimeAsDate: true}, "Date | null"},
+		{"[]time.Time", &parser.Config{TimeAsDate: true}, "Date[]"},
+		{"[]*time.Time", &parser.Config{TimeAsDate: true}, "(Date | null)[]"},
+		{"map[string]time.Time", &parser.Config{TimeAsDate: true}, "{ [key: string]: Date }"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType, map[string]string{}, nil,
+			map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}, tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFilesWithOptions_NonRecursive(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"user.go":             "package model\n\ntype User struct{ ID int }\n",
+		"nested/order.go":     "package nested\n\ntype Order struct{ ID int }\n",
+		"nested/deep/item.go": "package deep\n\ntype Item struct{ ID int }\n",
+		"testdata/fixture.go": "package testdata\n\ntype Fixture struct{ ID int }\n",
+	})
+
+	tests := []struct {
+		name string
+		opts parser.ParseGoFilesOptions
+		want []string
+	}{
+		{"Recursive", parser.ParseGoFilesOptions{}, []string{"Item", "Order", "User"}},
+		{"NonRecursive", parser.ParseGoFilesOptions{NonRecursive: true}, []string{"User"}},
+		{"NonRecursiveAllDirs", parser.ParseGoFilesOptions{NonRecursive: true, AllDirs: true}, []string{"User"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parser.ParseGoFilesWithOptions(dir, tt.opts)
+			if err != nil {
+				t.Fatalf("ParseGoFilesWithOptions failed: %v", err)
+			}
+			var names []string
+			for _, s := range data.Structs {
+				names = append(names, s.Name)
+			}
+			slices.Sort(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("structs = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoDirs(t *testing.T) {
+	api := writeTree(t, map[string]string{
+		"model.go": "package model\n\ntype User struct {\n\tID Ref\n}\n\ntype Ref = string\n",
+	})
+	shared := writeTree(t, map[string]string{
+		"types.go": "package types\n\n// User is shared.\ntype User struct {\n\tID Ref // the ID\n}\n\ntype Ref = string\n\ntype Money struct{ Cents int64 }\n",
+	})
+
+	data, err := parser.ParseGoDirs([]string{api, shared})
+	if err != nil {
+		t.Fatalf("ParseGoDirs failed: %v", err)
+	}
+	var structs, aliases []string
+	for _, s := range data.Structs {
+		structs = append(structs, s.Name)
+	}
+	for _, a := range data.Aliases {
+		aliases = append(aliases, a.Name)
+	}
+	if want := []string{"User", "Money"}; !reflect.DeepEqual(structs, want) {
+		t.Errorf("structs = %v, want %v", structs, want)
+	}
+	if want := []string{"Ref"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("aliases = %v, want %v", aliases, want)
+	}
+
+	conflicting := writeTree(t, map[string]string{
+		"user.go": "package other\n\ntype User struct {\n\tID   Ref\n\tName string\n}\n",
+	})
+	_, err = parser.ParseGoDirs([]string{api, conflicting})
+	if err == nil || !strings.Contains(err.Error(), `struct "User" is declared differently in `+api+" and "+conflicting) {
+		t.Errorf("expected a conflict naming both directories, got %v", err)
+	}
+
+	otherRef := writeTree(t, map[string]string{"ref.go": "package other\n\ntype Ref = int\n"})
+	if _, err := parser.ParseGoDirs([]string{api, otherRef}); err == nil || !strings.Contains(err.Error(), `type "Ref"`) {
+		t.Errorf("expected an alias conflict, got %v", err)
+	}
+
+	// a name may not change kind between directories either
+	userAlias := writeTree(t, map[string]string{"user.go": "package other\n\ntype User = string\n"})
+	if _, err := parser.ParseGoDirs([]string{api, userAlias}); err == nil || !strings.Contains(err.Error(), `type "User" is declared differently in `+api+" and "+userAlias) {
+		t.Errorf("expected a conflict between a struct and an alias, got %v", err)
+	}
+}
+
+func TestParseGoFiles_TypeDocs(t *testing.T) {
+	dir := t.TempDir()
+	src := `package dto
+
+// Person is a person.
+//
+// It has a name.
+//go2ts:nullable
+type Person struct{ Name string }
+
+type (
+	// ID identifies a Person.
+	ID string
+
+	Undocumented struct{}
+)
+
+// Group doc of several types is not theirs.
+type (
+	A int
+	B int
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "dto.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+
+	docs := map[string]string{}
+	for _, s := range data.Structs {
+		docs[s.Name] = s.Doc
+	}
+	for _, a := range data.Aliases {
+		docs[a.Name] = a.Doc
+	}
+	want := map[string]string{
+		"Person":       "Person is a person.\n\nIt has a name.",
+		"ID":           "ID identifies a Person.",
+		"Undocumented": "",
+		"A":            "",
+		"B":            "",
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("docs = %q, want %q", docs, want)
+	}
+}
+
+func TestParseGoFiles_Marshalers(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Custom struct{ Name string }
+
+func (c Custom) MarshalJSON() ([]byte, error) { return nil, nil }
+
+type Named[T any] struct{ V T }
+
+func (n *Named[T]) MarshalJSON() (data []byte, err error) { return nil, nil }
+
+type Text struct{ V string }
+
+func (t Text) MarshalText() ([]byte, error) { return nil, nil }
+
+type Odd struct{ V string }
+
+func (o Odd) MarshalJSON() string { return "" }
+`
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write model.go: %v", err)
+	}
+
+	data, err := parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	got := map[string]bool{}
+	for _, s := range data.Structs {
+		got[s.Name] = s.Marshaler
+	}
+	want := map[string]bool{"Custom": true, "Named": true, "Text": false, "Odd": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Marshaler = %v, want %v", got, want)
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_OverridePrecedence(t *testing.T) {
+	cfg := &parser.Config{
+		TimeAsDate:  true,
+		URLAsObject: true,
+		Overrides: map[string]string{
+			"money.Amount":       "string",
+			"geo.Point":          "GeoPoint",
+			"time.Time":          "number",
+			"url.URL":            "string",
+			"uuid.UUID":          "UUID",
+			"primitive.ObjectID": "ObjectId",
+			"int64":              "bigint",
+		},
+	}
+	structMap := map[string]parser.StructInfo{"Page": {Name: "Page", TypeParams: []string{"T"}}}
+
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"money.Amount", "string"},
+		{"*geo.Point", "GeoPoint | null"},
+		{"[]geo.Point", "GeoPoint[]"},
+		{"map[string]*geo.Point", "{ [key: string]: (GeoPoint | null) }"},
+		{"Page[geo.Point]", "Page<GeoPoint>"},
+		{"time.Time", "number"},
+		{"url.URL", "string"},
+		{"uuid.UUID", "UUID"},
+		{"[]primitive.ObjectID", "ObjectId[]"},
+		{"int64", "bigint"},
+		{"int32", "number"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType,
+			map[string]string{},
+			nil,
+			structMap,
+			map[string]string{},
+			map[string]bool{},
+			cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q) = %q, want %q", tc.goType, got, tc.want)
+		}
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	got, err := parser.ParseOverrides("money.Amount=string, geo.Point={ lat: number, lng: number },Meta=Record<string, any>")
+	if err != nil {
+		t.Fatalf("ParseOverrides failed: %v", err)
+	}
+	want := map[string]string{
+		"money.Amount": "string",
+		"geo.Point":    "{ lat: number, lng: number }",
+		"Meta":         "Record<string, any>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOverrides = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"money.Amount", "=string", "money.Amount="} {
+		if _, err := parser.ParseOverrides(spec); err == nil {
+			t.Errorf("ParseOverrides(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestGoTypeToTSTypeWithConfig_PreserveNamedAliases(t *testing.T) {
+	aliasMap := map[string]string{
+		"Email":     "string",
+		"CustomInt": "int",
+		"Emails":    "[]Email",
+		"Box":       "Wrapper[T]",
+	}
+	structMap := map[string]parser.StructInfo{"Wrapper": {Name: "Wrapper", TypeParams: []string{"T"}}}
+
+	tests := []struct {
+		goType string
+		cfg    *parser.Config
+		want   string
+	}{
+		{"Email", nil, "string"},
+		{"[]Emails", nil, "string[][]"},
+		{"Email", &parser.Config{PreserveNamedAliases: true}, "Email"},
+		{"*CustomInt", &parser.Config{PreserveNamedAliases: true}, "CustomInt | null"},
+		{"map[string][]Email", &parser.Config{PreserveNamedAliases: true}, "{ [key: string]: Email[] }"},
+		{"Emails", &parser.Config{PreserveNamedAliases: true}, "Emails"},
+		{"Box[Email]", &parser.Config{PreserveNamedAliases: true}, "Box<Email>"},
+		{"Wrapper[CustomInt]", &parser.Config{PreserveNamedAliases: true}, "Wrapper<CustomInt>"},
+		{"string", &parser.Config{PreserveNamedAliases: true}, "string"},
+	}
+	for _, tc := range tests {
+		got := parser.GoTypeToTSTypeWithConfig(tc.goType,
+			aliasMap,
+			nil,
+			structMap,
+			map[string]string{},
+			map[string]bool{},
+			tc.cfg)
+		if got != tc.want {
+			t.Errorf("GoTypeToTSTypeWithConfig(%q, %+v) = %q, want %q", tc.goType, tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestParseGoFiles_ImportPath(t *testing.T) {
+	user := "package model\n\ntype User struct{ ID int }\n"
+	root := writeTree(t, map[string]string{
+		"go.mod":          "module example.com/api // the API\n\ngo 1.23\n",
+		"model/user.go":   user,
+		"legacy/model.go": "package model\n\ntype Status string\n",
+	})
+
+	data, err := parser.ParseGoFiles(root)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if len(data.Structs) != 1 || data.Structs[0].ImportPath != "example.com/api/model" {
+		t.Errorf("unexpected structs %+v", data.Structs)
+	}
+	if len(data.Aliases) != 1 || data.Aliases[0].ImportPath != "example.com/api/legacy" || data.Aliases[0].Package != "model" {
+		t.Errorf("unexpected aliases %+v", data.Aliases)
+	}
+
+	// outside of a module, the directory stands for the import path
+	dir := filepath.Join(writeTree(t, map[string]string{"model/user.go": user}), "model")
+	data, err = parser.ParseGoFiles(dir)
+	if err != nil {
+		t.Fatalf("ParseGoFiles failed: %v", err)
+	}
+	if want := filepath.ToSlash(dir); data.Structs[0].ImportPath != want {
+		t.Errorf("ImportPath = %q, want %q", data.Structs[0].ImportPath, want)
+	}
+}
+
+func TestUnregisterTypeMapping(t *testing.T) {
+	parser.RegisterTypeMapping("geo.Point", "GeoPoint")
+	if got := parser.GoTypeToTSType("geo.Point", map[string]string{}, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}); got != "GeoPoint" {
+		t.Errorf("registered mapping: got %q", got)
+	}
+	parser.UnregisterTypeMapping("geo.Point")
+	if _, ok := parser.KnownTypeMappings()["geo.Point"]; ok {
+		t.Error("expected the mapping to be removed")
+	}
+	if got := parser.GoTypeToTSType("geo.Point", map[string]string{}, nil, map[string]parser.StructInfo{}, map[string]string{}, map[string]bool{}); got != "any" {
+		t.Errorf("unregistered mapping: got %q, want any", got)
+	}
+}
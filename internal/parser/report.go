@@ -0,0 +1,90 @@
+package parser
+
+import "fmt"
+
+// DiagnosticKind classifies a Diagnostic.
+type DiagnosticKind string
+
+// Diagnostic kinds.
+const (
+	KindLossy      DiagnosticKind = "lossy"      // conversion dropped type information
+	KindUnresolved DiagnosticKind = "unresolved" // reference to a type that was not scanned
+)
+
+// Diagnostic describes a lossy conversion made while mapping a Go type.
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Scope   string // declaration being converted, e.g. "UserAccount.Metadata"
+	GoType  string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Scope == "" {
+		return fmt.Sprintf("%s: %s", d.GoType, d.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", d.Scope, d.GoType, d.Message)
+}
+
+// Report collects diagnostics produced during conversion.
+// Scope is attached to every diagnostic added until it is changed.
+type Report struct {
+	Scope       string
+	Diagnostics []Diagnostic
+}
+
+func (r *Report) add(kind DiagnosticKind, goType, format string, args ...any) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{
+		Kind:    kind,
+		Scope:   r.Scope,
+		GoType:  goType,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Filter returns the diagnostics of the given kind.
+func (r *Report) Filter(kind DiagnosticKind) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range r.Diagnostics {
+		if d.Kind == kind {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// report records a lossy conversion when cfg carries a Report.
+func (c *Config) report(goType, format string, args ...any) {
+	if c == nil || c.Report == nil {
+		return
+	}
+	c.Report.add(KindLossy, goType, format, args...)
+}
+
+// Lossy records a lossy conversion of goType made outside the type mapping,
+// e.g. by an output format that cannot express it.
+func (c *Config) Lossy(goType, format string, args ...any) {
+	c.report(goType, format, args...)
+}
+
+// UnresolvedType is emitted where a Go type resolves to nothing, e.g. a
+// malformed or unsupported expression, so the output still type-checks.
+const UnresolvedType = "never"
+
+// Resolved - returns ts, or UnresolvedType when the conversion of goType
+// produced nothing, recording a diagnostic.
+func (c *Config) Resolved(ts, goType string) string {
+	if ts != "" {
+		return ts
+	}
+	c.report(goType, "unresolvable type converted to %s", UnresolvedType)
+	return UnresolvedType
+}
+
+// reportUnresolved records a reference to a type missing from the scanned set.
+func (c *Config) reportUnresolved(goType, name string) {
+	if c == nil || c.Report == nil {
+		return
+	}
+	c.Report.add(KindUnresolved, goType, "unresolved reference to %s", name)
+}
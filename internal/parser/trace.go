@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceStep is one conversion of a Go type, nested Depth levels deep in the
+// conversion of the declaration Scope.
+type TraceStep struct {
+	Scope  string
+	Depth  int
+	GoType string
+	TSType string
+}
+
+// Trace collects every recursive conversion when set on Config, in call
+// order, to show how a type was resolved.
+// Scope is attached to every step added until it is changed.
+type Trace struct {
+	Scope string
+	Steps []TraceStep
+
+	depth int
+}
+
+// String renders the steps one per line, indented by depth, with a header
+// line for each scope.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	scope := ""
+	for i, s := range t.Steps {
+		if s.Scope != scope || i == 0 {
+			scope = s.Scope
+			sb.WriteString(scope + ":\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s%s → %s\n", strings.Repeat("  ", s.Depth+1), s.GoType, s.TSType))
+	}
+	return sb.String()
+}
+
+// traced runs convert and records it as a step of c.Trace, which must be set.
+// Steps are recorded before their nested conversions.
+func (c *Config) traced(goType string, convert func() string) string {
+	t := c.Trace
+	i := len(t.Steps)
+	t.Steps = append(t.Steps, TraceStep{Scope: t.Scope, Depth: t.depth, GoType: goType})
+	t.depth++
+	ts := convert()
+	t.depth--
+	t.Steps[i].TSType = ts
+	return ts
+}
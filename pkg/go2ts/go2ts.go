@@ -3,20 +3,251 @@ package go2ts
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
 
 	"github.com/limbicnode/go2ts/internal/generator"
 	"github.com/limbicnode/go2ts/internal/parser"
 )
 
+// Version is the go2ts release, set at build time with
+// -ldflags "-X github.com/limbicnode/go2ts/pkg/go2ts.Version=v1.2.3".
+var Version = "dev"
+
+// GenerateOptions controls how the TypeScript output is produced.
+type GenerateOptions = generator.Options
+
+// ParseOptions controls which Go declarations are extracted.
+type ParseOptions = parser.ParseGoFilesOptions
+
+// Options combines the parse and generate options of a conversion.
+type Options struct {
+	GenerateOptions
+	ParseOptions
+}
+
+// PackageOptions overrides GenerateOptions for the types of one Go package.
+type PackageOptions = generator.PackageOptions
+
+// LoadPackageOptions - reads the PackageOptions of GenerateOptions.Packages,
+// keyed by import path, from a JSON file.
+func LoadPackageOptions(path string) (map[string]PackageOptions, error) {
+	return generator.LoadPackageOptions(path)
+}
+
+// FieldCase selects the case of property names taken from Go field names.
+type FieldCase = generator.FieldCase
+
+// Field cases for GenerateOptions.FieldCase.
+const (
+	CaseGo            = generator.CaseGo
+	CaseCamel         = generator.CaseCamel
+	CaseCamelAcronyms = generator.CaseCamelAcronyms
+)
+
+// Separator selects the character separating the members of object types.
+type Separator = generator.Separator
+
+// Separators for GenerateOptions.Separator.
+const (
+	SeparatorSemicolon = generator.SeparatorSemicolon
+	SeparatorComma     = generator.SeparatorComma
+	SeparatorNone      = generator.SeparatorNone
+)
+
+// PointerStyle selects how pointer fields are declared.
+type PointerStyle = generator.PointerStyle
+
+// Pointer styles for GenerateOptions.Pointers.
+const (
+	PointerNullable         = generator.PointerNullable
+	PointerOptional         = generator.PointerOptional
+	PointerOptionalNullable = generator.PointerOptionalNullable
+)
+
+// EmbedStrategy selects how the fields of embedded structs are declared.
+type EmbedStrategy = generator.EmbedStrategy
+
+// Embed strategies for GenerateOptions.EmbedStrategy.
+const (
+	EmbedExtendsOmit = generator.EmbedExtendsOmit
+	EmbedExtends     = generator.EmbedExtends
+	EmbedFlatten     = generator.EmbedFlatten
+)
+
+// Emit selects which declarations are written to the output.
+type Emit = generator.Emit
+
+// Emit modes for GenerateOptions.Emit.
+const (
+	EmitAll    = generator.EmitAll
+	EmitTypes  = generator.EmitTypes
+	EmitValues = generator.EmitValues
+)
+
+// Config controls the Go to TypeScript type mapping.
+type Config = parser.Config
+
+// SetStyle selects the TypeScript form of map[K]struct{}.
+type SetStyle = parser.SetStyle
+
+// Set styles for Config.Sets.
+const (
+	SetAsMap    = parser.SetAsMap
+	SetAsSet    = parser.SetAsSet
+	SetAsRecord = parser.SetAsRecord
+)
+
+// Report collects diagnostics for lossy conversions when set on Config.
+type Report = parser.Report
+
+// Trace records every recursive type conversion when set on Config.
+type Trace = parser.Trace
+
+// Diagnostic describes a single lossy conversion.
+type Diagnostic = parser.Diagnostic
+
+// RegisterTypeMapping - maps goType, e.g. "money.Amount", to tsType in every
+// conversion, replacing any earlier mapping of goType.
+func RegisterTypeMapping(goType, tsType string) {
+	parser.RegisterTypeMapping(goType, tsType)
+}
+
+// RegisterTypeMappings - registers every mapping of m, e.g. a preset.
+func RegisterTypeMappings(m map[string]string) {
+	parser.RegisterTypeMappings(m)
+}
+
+// ParseOverrides - parses comma-separated goType=tsType mappings for
+// Config.Overrides, e.g. "money.Amount=string,geo.Point=GeoPoint".
+func ParseOverrides(spec string) (map[string]string, error) {
+	return parser.ParseOverrides(spec)
+}
+
+// UnregisterTypeMapping - removes the mapping of goType, e.g. to restore the
+// registry after a test.
+func UnregisterTypeMapping(goType string) {
+	parser.UnregisterTypeMapping(goType)
+}
+
+// KnownTypeMappings - returns a copy of the registered type mappings.
+func KnownTypeMappings() map[string]string {
+	return parser.KnownTypeMappings()
+}
+
+// PresetMongo - returns the mappings of the MongoDB driver's BSON types, e.g.
+// primitive.ObjectID to string. Registered by default.
+func PresetMongo() map[string]string {
+	return parser.PresetMongo()
+}
+
+// PresetSQL - returns the mappings of the database/sql null types and the
+// PostgreSQL driver types, e.g. sql.NullString to string | null. Registered by default.
+func PresetSQL() map[string]string {
+	return parser.PresetSQL()
+}
+
+// PresetCommon - returns the mappings of common value types such as
+// decimal.Decimal and uuid.UUID. Registered by default.
+func PresetCommon() map[string]string {
+	return parser.PresetCommon()
+}
+
 // Convert - converts Go structs in the input directory to TypeScript types in the output file.
 func Convert(inputDir, outputFile string) error {
-	data, err := parser.ParseGoFiles(inputDir)
+	return ConvertWithOptions(inputDir, outputFile, Options{})
+}
+
+// ConvertWithOptions - converts Go structs in the input directory to TypeScript types using the given options.
+func ConvertWithOptions(inputDir, outputFile string, opts Options) error {
+	return ConvertToFiles(inputDir, []string{outputFile}, opts)
+}
+
+// ConvertToFiles - converts Go structs in the input directory to TypeScript types
+// once and writes them to every output file, each atomically.
+func ConvertToFiles(inputDir string, outputFiles []string, opts Options) error {
+	return ConvertDirsToFiles([]string{inputDir}, outputFiles, opts)
+}
+
+// ConvertDirsToFiles - like ConvertToFiles, converting the Go structs of every input
+// directory into one output. A type declared differently in two directories is an error.
+func ConvertDirsToFiles(inputDirs, outputFiles []string, opts Options) error {
+	data, err := parseDirs(inputDirs, opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse Go files in %q: %w", inputDir, err)
+		return err
 	}
-	err = generator.GenerateTypeScript(data, outputFile)
+	err = generator.GenerateTypeScriptToFiles(data, outputFiles, opts.GenerateOptions)
 	if err != nil {
-		return fmt.Errorf("failed to generate TypeScript file %q: %w", outputFile, err)
+		return fmt.Errorf("failed to generate TypeScript file %q: %w", strings.Join(outputFiles, ", "), err)
 	}
 	return nil
 }
+
+func parseDirs(inputDirs []string, opts Options) (parser.GoFileData, error) {
+	parseOpts := opts.ParseOptions
+	if parseOpts.Diagnostics == nil {
+		parseOpts.Diagnostics = opts.GenerateOptions.Report // one report for the whole conversion
+	}
+	data, err := parser.ParseGoDirsWithOptions(inputDirs, parseOpts)
+	if err != nil {
+		return data, fmt.Errorf("failed to parse Go files in %q: %w", strings.Join(inputDirs, ", "), err)
+	}
+	return data, nil
+}
+
+// ConvertToWriter - converts Go structs in the input directory to TypeScript types
+// written to w, e.g. os.Stdout.
+func ConvertToWriter(inputDir string, w io.Writer) error {
+	return ConvertDirsToWriter([]string{inputDir}, w, Options{})
+}
+
+// ConvertDirsToWriter - converts the Go structs of every input directory into one
+// output written to w, using the given options. Merge is ignored. The file
+// conversions render through the same generator writer before their atomic
+// writes.
+func ConvertDirsToWriter(inputDirs []string, w io.Writer, opts Options) error {
+	data, err := parseDirs(inputDirs, opts)
+	if err != nil {
+		return err
+	}
+	if err := generator.GenerateTypeScriptToWithOptions(data, w, opts.GenerateOptions); err != nil {
+		return fmt.Errorf("failed to generate TypeScript: %w", err)
+	}
+	return nil
+}
+
+// PackageDir - resolves a Go import path, e.g. "github.com/me/proj/internal/model",
+// to the directory of its source as seen from the current module.
+func PackageDir(importPath string) (string, error) {
+	return parser.PackageDir(importPath)
+}
+
+// IsImportPath - reports whether in is a Go import path rather than a directory.
+func IsImportPath(in string) bool {
+	return parser.IsImportPath(in)
+}
+
+// ConvertModule - downloads a Go module ("path@version") through the module proxy
+// and converts the Go structs found in subDir of the module to TypeScript types.
+func ConvertModule(module, subDir, outputFile string, opts Options) error {
+	return ConvertModuleToFiles(module, subDir, []string{outputFile}, opts)
+}
+
+// ConvertModuleToFiles - like ConvertModule, writing the TypeScript types to every output file.
+func ConvertModuleToFiles(module, subDir string, outputFiles []string, opts Options) error {
+	moduleDir, err := parser.DownloadModule(module)
+	if err != nil {
+		return fmt.Errorf("failed to download module %q: %w", module, err)
+	}
+	return ConvertToFiles(filepath.Join(moduleDir, subDir), outputFiles, opts)
+}
+
+// ConvertModuleToWriter - like ConvertModule, writing the TypeScript types to w.
+func ConvertModuleToWriter(module, subDir string, w io.Writer, opts Options) error {
+	moduleDir, err := parser.DownloadModule(module)
+	if err != nil {
+		return fmt.Errorf("failed to download module %q: %w", module, err)
+	}
+	return ConvertDirsToWriter([]string{filepath.Join(moduleDir, subDir)}, w, opts)
+}
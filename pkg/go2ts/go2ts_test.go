@@ -1,6 +1,7 @@
 package go2ts_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -57,11 +58,205 @@ func TestConvert_ParseGoFilesError_Concrete(t *testing.T) {
 func TestConvert_GenerateTypeScriptError(t *testing.T) {
 	// correct directory input
 	inputDir := filepath.Join("..", "..", "test", "testdata", "model")
-	//  Output path may be unwritable or invalid
-	outputFile := "/root/forbidden/types.ts"
+	// the parent of the output path is a regular file, so no directory can
+	// be created for it whatever the permissions
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	outputFile := filepath.Join(parent, "types.ts")
 
 	err := go2ts.Convert(inputDir, outputFile)
 	if err == nil || !strings.Contains(err.Error(), "failed to generate TypeScript") {
 		t.Errorf("expected generate error, got %v", err)
 	}
 }
+
+func TestConvertWithOptions_Merge(t *testing.T) {
+	inputDir := filepath.Join("..", "..", "test", "testdata", "model")
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	if err := os.WriteFile(outputFile, []byte("export type Manual = string;\n"), 0644); err != nil {
+		t.Fatalf("failed to write existing file: %v", err)
+	}
+
+	var opts go2ts.Options
+	opts.Merge = true
+	if err := go2ts.ConvertWithOptions(inputDir, outputFile, opts); err != nil {
+		t.Fatalf("ConvertWithOptions failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "export type Manual = string;\n") {
+		t.Errorf("manual content was not preserved")
+	}
+	if !strings.Contains(string(out), "// go2ts:begin") || !strings.Contains(string(out), "// go2ts:end") {
+		t.Errorf("merge markers missing from output")
+	}
+}
+
+func TestConvertModule_DownloadError(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+
+	err := go2ts.ConvertModule("example.invalid/go2ts/missing@v1.0.0", ".", outputFile, go2ts.Options{})
+	if err == nil || !strings.Contains(err.Error(), "failed to download module") {
+		t.Errorf("expected download error, got %v", err)
+	}
+}
+
+func TestConvertWithOptions_VarStructs(t *testing.T) {
+	inputDir := t.TempDir()
+	src := `package config
+
+var defaultConfig = struct {
+	Port int ` + "`json:\"port\"`" + `
+}{Port: 8080}
+`
+	if err := os.WriteFile(filepath.Join(inputDir, "config.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write config.go: %v", err)
+	}
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+
+	var opts go2ts.Options
+	opts.VarStructs = true
+	if err := go2ts.ConvertWithOptions(inputDir, outputFile, opts); err != nil {
+		t.Fatalf("ConvertWithOptions failed: %v", err)
+	}
+
+	out, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(out), "export interface DefaultConfig {\n  port: number;\n}") {
+		t.Errorf("expected DefaultConfig interface, got:\n%s", out)
+	}
+}
+
+func TestRegisterTypeMapping(t *testing.T) {
+	known := go2ts.KnownTypeMappings()
+	for _, preset := range []map[string]string{go2ts.PresetMongo(), go2ts.PresetSQL(), go2ts.PresetCommon()} {
+		for goType, tsType := range preset {
+			if known[goType] != tsType {
+				t.Errorf("KnownTypeMappings()[%q] = %q, want preset mapping %q", goType, known[goType], tsType)
+			}
+		}
+	}
+
+	inputDir := t.TempDir()
+	src := `package billing
+
+type Invoice struct {
+	Total money.Amount ` + "`json:\"total\"`" + `
+	ID    primitive.ObjectID ` + "`json:\"id\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(inputDir, "invoice.go"), []byte(src), 0o600); err != nil {
+		t.Fatalf("failed to write invoice.go: %v", err)
+	}
+	// the registry is global: restore it for the other tests
+	t.Cleanup(func() {
+		for goType := range go2ts.KnownTypeMappings() {
+			if _, ok := known[goType]; !ok {
+				go2ts.UnregisterTypeMapping(goType)
+			}
+		}
+		go2ts.RegisterTypeMappings(known)
+	})
+	go2ts.RegisterTypeMapping("money.Amount", "string")
+
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	if err := go2ts.Convert(inputDir, outputFile); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	out, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(out), "  total: string;\n  id: string;\n") {
+		t.Errorf("expected registered and preset mappings, got:\n%s", out)
+	}
+}
+
+func TestConvertToFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputFiles := []string{
+		filepath.Join(tmpDir, "web", "types.ts"),
+		filepath.Join(tmpDir, "admin", "src", "types.ts"),
+	}
+	inputDir := filepath.Join("..", "..", "test", "testdata", "model")
+
+	if err := go2ts.ConvertToFiles(inputDir, outputFiles, go2ts.Options{}); err != nil {
+		t.Fatalf("ConvertToFiles failed: %v", err)
+	}
+
+	web, err := os.ReadFile(outputFiles[0])
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	admin, err := os.ReadFile(outputFiles[1])
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if len(web) == 0 || string(web) != string(admin) {
+		t.Errorf("expected the same non-empty output in both files, got %d and %d bytes", len(web), len(admin))
+	}
+
+	for _, dir := range []string{filepath.Dir(outputFiles[0]), filepath.Dir(outputFiles[1])} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("expected only types.ts in %s, found %d entries", dir, len(entries))
+		}
+	}
+}
+
+func TestConvertToWriter(t *testing.T) {
+	inputDir := filepath.Join("..", "..", "test", "testdata", "model")
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+	if err := go2ts.Convert(inputDir, outputFile); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	want, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := go2ts.ConvertToWriter(inputDir, &buf); err != nil {
+		t.Fatalf("ConvertToWriter failed: %v", err)
+	}
+	// the headers differ only by the generation time
+	_, gotBody, _ := strings.Cut(buf.String(), "\n")
+	_, wantBody, _ := strings.Cut(string(want), "\n")
+	if gotBody == "" || gotBody != wantBody {
+		t.Errorf("expected the file output, got:\n%s", buf.String())
+	}
+}
+
+func TestConvertDirsToFiles(t *testing.T) {
+	shared := t.TempDir()
+	src := "package types\n\ntype Money struct {\n\tCents int64 `json:\"cents\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(shared, "money.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inputDirs := []string{filepath.Join("..", "..", "test", "testdata", "model"), shared}
+	outputFile := filepath.Join(t.TempDir(), "types.ts")
+
+	if err := go2ts.ConvertDirsToFiles(inputDirs, []string{outputFile}, go2ts.Options{}); err != nil {
+		t.Fatalf("ConvertDirsToFiles failed: %v", err)
+	}
+	out, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, want := range []string{"export interface UserAccount {", "export interface Money {\n  cents: number;\n}"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+
+export interface FooData {
+  A: number;
+}
+
+export interface BarData {
+  B: string;
+}
+
+export type Webhook =
+  | {
+    type: "foo";
+    foo: FooData;
+    delivery_attempts: number;
+    endpoint: {
+      Scheme: string;
+      Opaque: string;
+      User: any;
+      Host: string;
+      Path: string;
+      RawPath: string;
+      OmitHost: boolean;
+      ForceQuery: boolean;
+      RawQuery: string;
+      Fragment: string;
+      RawFragment: string;
+    };
+  }
+  | {
+    type: "bar";
+    bar: BarData;
+    delivery_attempts: number;
+    endpoint: {
+      Scheme: string;
+      Opaque: string;
+      User: any;
+      Host: string;
+      Path: string;
+      RawPath: string;
+      OmitHost: boolean;
+      ForceQuery: boolean;
+      RawQuery: string;
+      Fragment: string;
+      RawFragment: string;
+    };
+  };
+
+export function isWebhookFoo(v: Webhook): v is Extract<Webhook, { type: "foo" }> {
+  return v.type === "foo";
+}
+
+export function isWebhookBar(v: Webhook): v is Extract<Webhook, { type: "bar" }> {
+  return v.type === "bar";
+}
+
+export interface Settings {
+  /** @example {"MaxConnections": 8} */
+  limits: {
+    MaxConnections: number;
+    MaxRequestsPerSecond: number;
+    Retry: { Attempts: number; BackoffMilliseconds: number; Jitter: boolean };
+  };
+}
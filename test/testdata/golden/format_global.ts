@@ -0,0 +1,40 @@
+
+declare global {
+  interface FooData {
+    A: number;
+  }
+
+  interface BarData {
+    B: string;
+  }
+
+  interface Webhook {
+    type: string;
+    foo: FooData | null;
+    bar: BarData | null;
+    delivery_attempts: number;
+    endpoint: {
+      Scheme: string;
+      Opaque: string;
+      User: any;
+      Host: string;
+      Path: string;
+      RawPath: string;
+      OmitHost: boolean;
+      ForceQuery: boolean;
+      RawQuery: string;
+      Fragment: string;
+      RawFragment: string;
+    };
+  }
+
+  interface Settings {
+    limits: {
+      MaxConnections: number;
+      MaxRequestsPerSecond: number;
+      Retry: { Attempts: number; BackoffMilliseconds: number; Jitter: boolean };
+    };
+  }
+}
+
+export {};
@@ -511,8 +511,10 @@ type MapWithCustomAndStructKeys struct {
 
 // 60. Generic with pointer slice
 type ResultWithPointerAndList struct {
-	Data GenericResult[*UserAccount]         `json:"data"`
-	List GenericResult[[]*UserProfileDetail] `json:"list"`
+	Data    GenericResult[*UserAccount]          `json:"data"`
+	List    GenericResult[[]*UserProfileDetail]  `json:"list"`
+	PtrData *GenericResult[*UserAccount]         `json:"ptr_data"`
+	PtrList *GenericResult[[]*UserProfileDetail] `json:"ptr_list"`
 }
 
 // 61. Generic with map